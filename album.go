@@ -0,0 +1,221 @@
+package musicfile
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// albumFile is the name of a file of the album split into the segments
+// between dashes.
+type albumFile struct {
+	stem     text
+	track    int
+	segments [][2]int
+}
+
+// ExtractAlbumInfo extracts music info from the paths of the files of one
+// directory. Each file is parsed like ExtractInfo first. Then the names of
+// the audio and lyrics files are compared: the segments between dashes that
+// all of them share at the beginning or at the end name the author, and
+// the album when there are two of them, the numbers that differ in every
+// file are the tracks and the rest is the work. This settles the names like
+// "Title - Artist" and "Artist - 01 - Title", which are ambiguous alone.
+// The infos are returned in the order of the paths.
+func ExtractAlbumInfo(filepaths [][]byte) []Info {
+	infos := make([]Info, len(filepaths))
+
+	var (
+		files   []albumFile
+		indices []int
+	)
+
+	for i, filepath := range filepaths {
+		path := SplitPath(filepath, PathAuto)
+
+		infos[i] = ExtractPathInfo(path)
+
+		if len(path) == 0 || (infos[i].Kind != KindAudio && infos[i].Kind != KindLyrics) {
+			continue
+		}
+
+		name, _ := prepare(path[len(path)-1])
+		_, stem, _ := basenameStem(name)
+
+		files = append(files, albumFile{stem: stem.trimSpace()})
+		indices = append(indices, i)
+	}
+
+	if len(files) < 2 {
+		return infos
+	}
+
+	author, album, ok := inferAlbum(files)
+	if !ok {
+		return infos
+	}
+
+	for k, file := range files {
+		info := &infos[indices[k]]
+
+		info.Author = author.String()
+		info.Work = file.work().String()
+
+		if file.track > 0 {
+			info.Track = file.track
+		}
+		if info.Album == "" && album != nil {
+			info.Album = album.String()
+		}
+	}
+
+	return infos
+}
+
+// inferAlbum finds the author and the album the files share and leaves
+// the segments of the work in the files.
+func inferAlbum(files []albumFile) (author, album *text, ok bool) {
+	inferTracks(files, func(f albumFile) (int, int, bool) {
+		m := trackNumberRe.FindSubmatchIndex(f.stem.b)
+		if m == nil {
+			return 0, 0, false
+		}
+		n, _ := strconv.Atoi(string(f.stem.b[m[2]:m[3]]))
+		return n, m[1], true
+	})
+
+	sep := dashRe
+	if allFiles(files, func(f albumFile) bool { return spacedDashRe.Match(f.stem.b) }) {
+		sep = spacedDashRe
+	}
+
+	for i := range files {
+		files[i].split(sep)
+	}
+
+	minSegments := len(files[0].segments)
+	for _, f := range files {
+		minSegments = min(minSegments, len(f.segments))
+	}
+
+	// The segments shared at the beginning and at the end,
+	// at least one segment is left for the work.
+	prefix := 0
+	for prefix < minSegments-1 && sameSegment(files, func(f albumFile) int { return prefix }) {
+		prefix++
+	}
+
+	suffix := 0
+	for prefix+suffix < minSegments-1 && sameSegment(files, func(f albumFile) int { return len(f.segments) - 1 - suffix }) {
+		suffix++
+	}
+
+	switch {
+	case prefix > 0:
+		author = files[0].segment(0)
+		if prefix > 1 {
+			album = files[0].segment(1)
+		}
+	case suffix > 0:
+		author = files[0].segment(len(files[0].segments) - 1)
+	default:
+		return nil, nil, false
+	}
+
+	for i := range files {
+		f := &files[i]
+		f.segments = f.segments[prefix : len(f.segments)-suffix]
+	}
+
+	// The numbers between the author and the work, like "Artist - 01 - Title".
+	if minSegments-prefix-suffix > 1 {
+		inferTracks(files, func(f albumFile) (int, int, bool) {
+			s := string(f.segment(0).b)
+			if len(s) > 3 || !isNumber(s) {
+				return 0, 0, false
+			}
+			n, _ := strconv.Atoi(s)
+			return n, 0, true
+		})
+	}
+
+	return author, album, true
+}
+
+// inferTracks sets the tracks when every file has a number and the numbers
+// differ, like the numbers of "50 Cent - Song" don't. The number function
+// returns the number, the end of the number in the stem, or 0 when
+// the number is the first segment, and whether there is one.
+func inferTracks(files []albumFile, number func(albumFile) (n, end int, ok bool)) {
+	seen := make(map[int]bool, len(files))
+
+	for _, f := range files {
+		n, _, ok := number(f)
+		if !ok || n == 0 {
+			return
+		}
+		seen[n] = true
+	}
+
+	if len(seen) < 2 {
+		return
+	}
+
+	for i := range files {
+		f := &files[i]
+
+		n, end, _ := number(*f)
+		f.track = n
+
+		if end > 0 {
+			f.stem = f.stem.slice(end, len(f.stem.b))
+		} else {
+			f.segments = f.segments[1:]
+		}
+	}
+}
+
+// split splits the stem into the segments between the separators.
+func (f *albumFile) split(sep *regexp.Regexp) {
+	f.segments = f.segments[:0]
+
+	start := 0
+	for _, loc := range sep.FindAllIndex(f.stem.b, -1) {
+		if loc[0] > start {
+			f.segments = append(f.segments, [2]int{start, loc[0]})
+		}
+		start = loc[1]
+	}
+
+	if start < len(f.stem.b) {
+		f.segments = append(f.segments, [2]int{start, len(f.stem.b)})
+	}
+}
+
+func (f albumFile) segment(i int) *text {
+	s := f.stem.slice(f.segments[i][0], f.segments[i][1]).trimSpace()
+	return &s
+}
+
+// work returns the segments left for the work with the separators between them.
+func (f albumFile) work() text {
+	return f.stem.slice(f.segments[0][0], f.segments[len(f.segments)-1][1]).trimSpace()
+}
+
+// sameSegment reports whether the segments at the index are the same in all the files.
+func sameSegment(files []albumFile, index func(albumFile) int) bool {
+	first := string(files[0].segment(index(files[0])).b)
+
+	return allFiles(files, func(f albumFile) bool {
+		return strings.EqualFold(string(f.segment(index(f)).b), first)
+	})
+}
+
+func allFiles(files []albumFile, fn func(albumFile) bool) bool {
+	for _, f := range files {
+		if !fn(f) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package musicfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractAlbumInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		filepaths []string
+		want      []Info
+	}{
+		{
+			name: "author after title",
+			filepaths: []string{
+				"Music/Song One - Artist.mp3",
+				"Music/Song Two - Artist.mp3",
+				"Music/cover.jpg",
+			},
+			want: []Info{
+				{Author: "Artist", Work: "Song One", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				{Author: "Artist", Work: "Song Two", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				{FileExtension: ".jpg", Kind: KindImage},
+			},
+		},
+		{
+			name: "numbers after author",
+			filepaths: []string{
+				"Artist - 01 - Intro.mp3",
+				"Artist - 02 - Part One - Part Two.mp3",
+				"Artist - 03 - Outro (Live).mp3",
+			},
+			want: []Info{
+				{Author: "Artist", Work: "Intro", Track: 1, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				{Author: "Artist", Work: "Part One - Part Two", Track: 2, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				{Author: "Artist", Work: "Outro", Track: 3, Tags: EmptyTags.Set(Live), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+			},
+		},
+		{
+			name: "author and album prefix",
+			filepaths: []string{
+				"01. Кино - Группа крови - Группа крови.flac",
+				"02. Кино - Группа крови - Закрой за мной дверь, я ухожу.flac",
+				"02. Кино - Группа крови - Группа крови.lrc",
+			},
+			want: []Info{
+				{Author: "Кино", Album: "Группа крови", Work: "Группа крови", Track: 1, FileExtension: ".flac", Family: FamilyFLAC, Kind: KindAudio},
+				{Author: "Кино", Album: "Группа крови", Work: "Закрой за мной дверь, я ухожу", Track: 2, FileExtension: ".flac", Family: FamilyFLAC, Kind: KindAudio},
+				{Author: "Кино", Album: "Группа крови", Work: "Группа крови", Track: 2, FileExtension: ".lrc", Kind: KindLyrics},
+			},
+		},
+		{
+			name: "shared suffix",
+			filepaths: []string{
+				"Jay-Z - Song - www.example.com.mp3",
+				"Jay-Z - Other Song - www.example.com.mp3",
+			},
+			want: []Info{
+				{Author: "Jay-Z", Work: "Song", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				{Author: "Jay-Z", Work: "Other Song", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+			},
+		},
+		{
+			name: "nothing shared",
+			filepaths: []string{
+				"A - Song.mp3",
+				"B - Other.mp3",
+			},
+			want: []Info{
+				{Author: "A", Work: "Song", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				{Author: "B", Work: "Other", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+			},
+		},
+		{
+			name: "single file",
+			filepaths: []string{
+				"Song - Artist.mp3",
+			},
+			want: []Info{
+				{Author: "Song", Work: "Artist", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var filepaths [][]byte
+			for _, p := range tt.filepaths {
+				filepaths = append(filepaths, []byte(p))
+			}
+
+			if got := ExtractAlbumInfo(filepaths); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractAlbumInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
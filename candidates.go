@@ -0,0 +1,165 @@
+package musicfile
+
+import (
+	"sort"
+	"strings"
+)
+
+// Candidate is one of the ways to read the name of a file, with the score
+// of how likely it is from 0 to 1 and the reason for it.
+type Candidate struct {
+	Info
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// Reasons of the parse of ExtractInfo by the separator between the author and the work.
+var splitReasons = map[splitKind]string{
+	splitNone:         "no separator, the name is the work",
+	splitDash:         "the author before the dash",
+	splitDashes:       "the author before the last dash",
+	splitUnspaced:     "the author before the dash without spaces",
+	splitUnspacedMany: "the author before the last dash without spaces",
+	splitInserted:     "the author before the separator of the naming convention",
+	splitQuotes:       "the work in the title quotes",
+	splitKnown:        "the author named by the directories",
+}
+
+// Scores of the alternative parses.
+const (
+	firstDashScore   = 0.45
+	middleDashScore  = 0.35
+	authorAfterScore = 0.4
+	albumScore       = 0.45
+	albumFirstScore  = 0.25
+	versionScore     = 0.7
+)
+
+// ExtractCandidates extracts up to n candidate parses of the path, the most
+// likely first. The first one is the info of ExtractInfo unless an
+// alternative is more likely. All the candidates are returned if n <= 0.
+func ExtractCandidates(filepath []byte, n int) []Candidate {
+	return ExtractPathCandidates(SplitPath(filepath, PathAuto), n)
+}
+
+// ExtractPathCandidates extracts up to n candidate parses of the path
+// segments like ExtractCandidates.
+func ExtractPathCandidates(path [][]byte, n int) []Candidate {
+	a := analyzePath(path)
+	scored := scoreAnalysis(a)
+
+	if !scored.Kind.describesTrack() {
+		return []Candidate{{Info: scored.Info, Score: 1, Reason: "the file doesn't name a track"}}
+	}
+
+	score := scored.Confidence[FieldWork]
+	if c, ok := scored.Confidence[FieldAuthor]; ok {
+		score = min(score, c)
+	}
+
+	candidates := []Candidate{{Info: scored.Info, Score: score, Reason: splitReasons[a.parse.split]}}
+
+	stem := a.parse.stem
+	if a.file.Track != 0 {
+		if loc := trackNumberRe.FindIndex(stem.b); loc != nil {
+			stem = stem.slice(loc[1], len(stem.b))
+		}
+	}
+
+	f := albumFile{stem: stem.trimSpace()}
+
+	sep := dashRe
+	if spacedDashRe.Match(f.stem.b) {
+		sep = spacedDashRe
+	}
+	f.split(sep)
+
+	k := len(f.segments)
+
+	add := func(author, album, work text, tags Tags, score float64, reason string) {
+		info := scored.Info
+		info.Author = author.String()
+		info.Work = work.String()
+		info.Tags = info.Tags.Append(tags)
+		info.Album = album.String()
+		if info.Album == "" {
+			info.Album, info.Year = albumDir(a.dirnames, info.Author)
+		}
+		candidates = append(candidates, Candidate{Info: info, Score: score, Reason: reason})
+	}
+
+	for i := 1; i < k; i++ {
+		score := middleDashScore
+		if i == 1 {
+			score = firstDashScore
+		}
+		add(f.join(0, i), text{}, f.join(i, k), EmptyTags, score, "the author before a dash")
+	}
+
+	for i := 1; i < k; i++ {
+		score := middleDashScore
+		if i == k-1 {
+			score = authorAfterScore
+		}
+		add(f.join(i, k), text{}, f.join(0, i), EmptyTags, score, "the author after a dash")
+	}
+
+	if k >= 3 {
+		add(f.join(0, 1), f.join(1, 2), f.join(2, k), EmptyTags, albumScore, "the author and the album before the work")
+		add(f.join(1, 2), f.join(0, 1), f.join(2, k), EmptyTags, albumFirstScore, "the album and the author before the work")
+
+		if tags := versionTags(f.join(k-1, k).String()); tags != EmptyTags {
+			add(f.join(0, 1), text{}, f.join(1, k-1), tags, versionScore, "the version after the work")
+		}
+	}
+
+	// The authors the directories name are the most likely ones.
+	authors := knownAuthors(a.dirnames)
+
+	for i := range candidates {
+		c := &candidates[i]
+		if c.Score < splitConfidence[splitKnown] && isKnownAuthor(c.Author, authors) {
+			c.Score = splitConfidence[splitKnown]
+			c.Reason += ", the directories name the author"
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	seen := make(map[Info]bool, len(candidates))
+	unique := candidates[:0]
+
+	for _, c := range candidates {
+		if seen[c.Info] {
+			continue
+		}
+		seen[c.Info] = true
+		unique = append(unique, c)
+	}
+
+	if n > 0 && len(unique) > n {
+		unique = unique[:n]
+	}
+
+	return unique
+}
+
+// join returns the segments from i to j with the separators between them.
+func (f albumFile) join(i, j int) text {
+	return f.stem.slice(f.segments[i][0], f.segments[j-1][1]).trimSpace()
+}
+
+func isKnownAuthor(author string, authors []string) bool {
+	if author == "" {
+		return false
+	}
+	author = foldString(author)
+	for _, a := range authors {
+		if strings.EqualFold(a, author) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package musicfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractCandidates(t *testing.T) {
+	type args struct {
+		filepath string
+		n        int
+	}
+	tests := []struct {
+		name string
+		args args
+		want []Candidate
+	}{
+		{
+			name: "title before artist",
+			args: args{"Title - Artist.mp3", 0},
+			want: []Candidate{
+				{Info: Info{Author: "Title", Work: "Artist", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.9, Reason: "the author before the dash"},
+				{Info: Info{Author: "Artist", Work: "Title", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.4, Reason: "the author after a dash"},
+			},
+		},
+		{
+			name: "known artist after title",
+			args: args{"Artist/Title - Artist.mp3", 1},
+			want: []Candidate{
+				{Info: Info{Author: "Artist", Work: "Title", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.95, Reason: "the author after a dash, the directories name the author"},
+			},
+		},
+		{
+			name: "album artist title",
+			args: args{"Album - Artist - Title.mp3", 3},
+			want: []Candidate{
+				{Info: Info{Author: "Album - Artist", Work: "Title", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.5, Reason: "the author before the last dash"},
+				{Info: Info{Author: "Album", Work: "Artist - Title", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.45, Reason: "the author before a dash"},
+				{Info: Info{Author: "Album", Album: "Artist", Work: "Title", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.45, Reason: "the author and the album before the work"},
+			},
+		},
+		{
+			name: "version after the work",
+			args: args{"03. Artist - Song - Live.mp3", 2},
+			want: []Candidate{
+				{Info: Info{Author: "Artist", Work: "Song", Track: 3, Tags: EmptyTags.Set(Live), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.7, Reason: "the version after the work"},
+				{Info: Info{Author: "Artist - Song", Work: "Live", Track: 3, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.5, Reason: "the author before the last dash"},
+			},
+		},
+		{
+			name: "album of the alternative author",
+			args: args{"Artist/Album/Title - Artist.mp3", 1},
+			want: []Candidate{
+				{Info: Info{Author: "Artist", Album: "Album", Work: "Title", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.95, Reason: "the author after a dash, the directories name the author"},
+			},
+		},
+		{
+			name: "work only",
+			args: args{"Song.mp3", 0},
+			want: []Candidate{
+				{Info: Info{Work: "Song", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}, Score: 0.8, Reason: "no separator, the name is the work"},
+			},
+		},
+		{
+			name: "not a track",
+			args: args{"cover.jpg", 0},
+			want: []Candidate{
+				{Info: Info{FileExtension: ".jpg", Kind: KindImage}, Score: 1, Reason: "the file doesn't name a track"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractCandidates([]byte(tt.args.filepath), tt.args.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractCandidates() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package musicfile
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+// abbreviations keep their dots when dots are used as spaces.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "st": true,
+	"jr": true, "sr": true, "feat": true, "ft": true, "vs": true,
+	"vol": true, "no": true, "op": true, "pt": true, "prod": true,
+}
+
+// spaceConvention rewrites names that use underscores or dots
+// instead of spaces, e.g. "Artist_-_Title" and "artist.-.title".
+func spaceConvention(name text) text {
+	name, _ = convertSpaces(name)
+	return name
+}
+
+// filenameConvention rewrites the file names like spaceConvention
+// and splits the rewritten names of two words into the author and
+// the work. Directory names are never split.
+func filenameConvention(name text) text {
+	name, ok := convertSpaces(name)
+	if !ok {
+		return name
+	}
+	return splitAuthorWork(name)
+}
+
+// convertSpaces rewrites the name and reports whether it used
+// underscores or dots instead of spaces.
+func convertSpaces(name text) (text, bool) {
+	spaces := bytes.Count(name.b, []byte{' '})
+	underscores := bytes.Count(name.b, []byte{'_'})
+	dots := bytes.Count(name.b, []byte{'.'})
+
+	switch {
+	case underscores > 0 && underscores > spaces:
+		return underscoresToSpaces(name), true
+	case spaces == 0 && underscores == 0 && dots >= 2:
+		return dotsToSpaces(name), true
+	default:
+		return name, false
+	}
+}
+
+// underscoresToSpaces replaces "__" with " - " and "_" with " ".
+func underscoresToSpaces(name text) text {
+	dst := name.slice(0, 0)
+
+	for {
+		i := bytes.IndexByte(name.b, '_')
+		if i < 0 {
+			break
+		}
+
+		dst = dst.append(name.slice(0, i))
+
+		if bytes.HasPrefix(name.b[i:], []byte("__")) {
+			dst = dst.appendLiteral(" - ")
+			name = name.slice(i+2, len(name.b))
+		} else {
+			dst = dst.appendLiteral(" ")
+			name = name.slice(i+1, len(name.b))
+		}
+	}
+
+	return dst.append(name)
+}
+
+// dotsToSpaces replaces dots with spaces, but keeps initials like "B.B."
+// and abbreviations like "feat.".
+func dotsToSpaces(name text) text {
+	dst := name.slice(0, 0)
+
+	for {
+		i := bytes.IndexByte(name.b, '.')
+		if i < 0 {
+			break
+		}
+
+		token := name.b[:i]
+		next := name.b[i+1:]
+		if j := bytes.IndexByte(next, '.'); j >= 0 {
+			next = next[:j]
+		}
+
+		dst = dst.append(name.slice(0, i))
+
+		switch {
+		case isInitial(token) && isInitial(next):
+			dst = dst.append(name.slice(i, i+1))
+		case isInitial(token) || abbreviations[string(bytes.ToLower(token))]:
+			dst = dst.append(name.slice(i, i+1)).appendLiteral(" ")
+		default:
+			dst = dst.appendLiteral(" ")
+		}
+
+		name = name.slice(i+1, len(name.b))
+	}
+
+	return dst.append(name)
+}
+
+func isInitial(token []byte) bool {
+	r, size := utf8.DecodeRune(token)
+	return size == len(token) && unicode.IsLetter(r)
+}
+
+// splitAuthorWork turns "01 author work" into "01 author - work".
+// Only the names with a track number and exactly two words are split,
+// "Hey_Jude" and "B.B.King" are titles or names.
+func splitAuthorWork(name text) text {
+	if bytes.IndexByte(name.b, '-') >= 0 {
+		return name
+	}
+
+	loc := trackPrefixRe.FindIndex(name.b)
+	if loc == nil || loc[1] == len(name.b) {
+		return name
+	}
+	start := loc[1]
+
+	rest := parenthesesRe.ReplaceAll(name.b[start:], nil)
+	if len(bytes.Fields(rest)) != 2 || bytes.ContainsAny(name.b[start:start+1], openBrackets) {
+		return name
+	}
+
+	// Find the end of the first word.
+	i := start
+	for i < len(name.b) && name.b[i] == ' ' {
+		i++
+	}
+	for i < len(name.b) && name.b[i] != ' ' {
+		i++
+	}
+
+	return name.slice(0, i).appendLiteral(" -").append(name.slice(i, len(name.b)))
+}
@@ -0,0 +1,148 @@
+package musicfile
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// legacyCharmaps are the legacy Cyrillic encodings names are repaired from.
+var legacyCharmaps = []*charmap.Charmap{
+	charmap.Windows1251,
+	charmap.KOI8R,
+	charmap.CodePage866,
+}
+
+// cyrillicFrequency lists the Russian letters from the most frequent one.
+const cyrillicFrequency = "оеаинтсрвлкмдпуяыьгзбчйхжшюцщэфъё"
+
+var cyrillicWeights = func() map[rune]int {
+	weights := make(map[rune]int)
+	n := utf8.RuneCountInString(cyrillicFrequency)
+	for i, r := range []rune(cyrillicFrequency) {
+		weights[r] = n - i
+	}
+	return weights
+}()
+
+// RepairEncoding converts names in the CP1251, KOI8-R and CP866 encodings,
+// and UTF-8 names wrongly decoded as Latin-1 or Windows-1252, to UTF-8.
+// It reports whether the name was repaired.
+func RepairEncoding(name []byte) ([]byte, bool) {
+	if !utf8.Valid(name) {
+		s, score := decodeLegacy(name)
+		if score <= 0 {
+			return name, false
+		}
+		return []byte(s), true
+	}
+
+	raw, ok := encodeLatin1(name)
+	if !ok {
+		return name, false
+	}
+
+	// Valid multi-byte sequences hardly ever come from real Latin-1 text.
+	if utf8.Valid(raw) {
+		return raw, true
+	}
+
+	s, score := decodeLegacy(raw)
+	if score <= 0 || score <= cyrillicScore(string(name)) {
+		return name, false
+	}
+
+	return []byte(s), true
+}
+
+// decodeLegacy decodes the name with the legacy encoding that scores best.
+func decodeLegacy(name []byte) (best string, bestScore int) {
+	for i, cm := range legacyCharmaps {
+		var b strings.Builder
+
+		for _, c := range name {
+			b.WriteRune(cm.DecodeByte(c))
+		}
+
+		s := b.String()
+		score := cyrillicScore(s)
+
+		if i == 0 || score > bestScore {
+			best, bestScore = s, score
+		}
+	}
+	return best, bestScore
+}
+
+// encodeLatin1 encodes the valid UTF-8 name back into the bytes it was
+// decoded from as Latin-1 or Windows-1252. It reports false when the name
+// has no such characters, or has characters these encodings don't have.
+func encodeLatin1(name []byte) ([]byte, bool) {
+	var (
+		dst   = make([]byte, 0, len(name))
+		found bool
+	)
+
+	for _, r := range string(name) {
+		switch {
+		case r < utf8.RuneSelf:
+			dst = append(dst, byte(r))
+		case r <= 0xFF:
+			dst = append(dst, byte(r))
+			found = true
+		default:
+			c, ok := charmap.Windows1252.EncodeRune(r)
+			if !ok {
+				return nil, false
+			}
+			dst = append(dst, c)
+			found = true
+		}
+	}
+
+	return dst, found
+}
+
+// cyrillicScore tells how much the text looks like Russian. Frequent letters
+// raise the score. Symbols, rare Cyrillic letters, letters of mixed
+// scripts within a word and capital letters after small ones lower it.
+func cyrillicScore(s string) (score int) {
+	var prev rune
+
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf:
+			if isLatinLetter(r) && unicode.Is(unicode.Cyrillic, prev) {
+				score -= 20
+			}
+		case unicode.Is(unicode.Cyrillic, r):
+			if w, ok := cyrillicWeights[unicode.ToLower(r)]; ok {
+				score += w
+			} else {
+				score -= 10
+			}
+			if isLatinLetter(prev) {
+				score -= 20
+			}
+			if unicode.IsUpper(r) && unicode.IsLower(prev) {
+				score -= 20
+			}
+		case unicode.Is(unicode.Latin, r):
+			if unicode.Is(unicode.Cyrillic, prev) {
+				score -= 20
+			}
+		case unicode.IsLetter(r) || unicode.IsSpace(r):
+		default:
+			score -= 50
+		}
+		prev = r
+	}
+
+	return score
+}
+
+func isLatinLetter(r rune) bool {
+	return unicode.Is(unicode.Latin, r)
+}
@@ -0,0 +1,81 @@
+package musicfile
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestRepairEncoding(t *testing.T) {
+	encode := func(cm *charmap.Charmap, s string) string {
+		b, err := cm.NewEncoder().String(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name         string
+		src          string
+		want         string
+		wantRepaired bool
+	}{
+		{
+			name: "ascii",
+			src:  "Artist - Song",
+			want: "Artist - Song",
+		},
+		{
+			name: "utf-8",
+			src:  "Ария - Штиль",
+			want: "Ария - Штиль",
+		},
+		{
+			name: "latin-1",
+			src:  "Beyoncé - Déjà Vu",
+			want: "Beyoncé - Déjà Vu",
+		},
+		{
+			name:         "cp1251",
+			src:          encode(charmap.Windows1251, "Кино - Группа крови"),
+			want:         "Кино - Группа крови",
+			wantRepaired: true,
+		},
+		{
+			name:         "koi8-r",
+			src:          encode(charmap.KOI8R, "Кино - Группа крови"),
+			want:         "Кино - Группа крови",
+			wantRepaired: true,
+		},
+		{
+			name:         "cp866",
+			src:          encode(charmap.CodePage866, "Кино - Группа крови"),
+			want:         "Кино - Группа крови",
+			wantRepaired: true,
+		},
+		{
+			name:         "cp1251 as latin-1",
+			src:          "Ðóññêèé ðîê",
+			want:         "Русский рок",
+			wantRepaired: true,
+		},
+		{
+			name:         "utf-8 as windows-1252",
+			src:          "ÐšÐ¸Ð½Ð¾ - Ð—Ð²ÐµÐ·Ð´Ð°",
+			want:         "Кино - Звезда",
+			wantRepaired: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, repaired := RepairEncoding([]byte(tt.src))
+			if string(got) != tt.want {
+				t.Errorf("RepairEncoding() got = %q, want %q", got, tt.want)
+			}
+			if repaired != tt.wantRepaired {
+				t.Errorf("RepairEncoding() repaired = %v, want %v", repaired, tt.wantRepaired)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package musicfile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Change is the change of a field made by a rule.
+type Change int
+
+const (
+	ChangeSet Change = iota
+	ChangeDelete
+)
+
+var changeNames = []string{
+	"set",
+	"delete",
+}
+
+func (c Change) String() string {
+	if c >= 0 && int(c) < len(changeNames) {
+		return changeNames[c]
+	}
+	return "unknown"
+}
+
+func (c Change) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Change) UnmarshalText(text []byte) error {
+	for i, name := range changeNames {
+		if name == string(text) {
+			*c = Change(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown change '%s'", text)
+}
+
+// TraceStep is a rule that matched a segment of the path and the change
+// it made. Start and End are the byte span of the match in the segment
+// after its encoding is repaired, they are -1 when the matched text was
+// inserted by a naming convention. Value is the value of the field or
+// the name of the tag.
+type TraceStep struct {
+	Rule    string `json:"rule"`
+	Segment int    `json:"segment"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Text    string `json:"text"`
+	Field   Field  `json:"field"`
+	Tag     TagBit `json:"-"`
+	Change  Change `json:"change"`
+	Value   string `json:"value"`
+}
+
+// Explanation is the info extracted from a path with the steps
+// of the rules that extracted it.
+type Explanation struct {
+	Path  []string    `json:"path"`
+	Info  Info        `json:"info"`
+	Steps []TraceStep `json:"steps"`
+}
+
+// Rules that split the author and the work.
+var splitRules = map[splitKind]string{
+	splitNone:         "whole name",
+	splitDash:         "spaced dash",
+	splitDashes:       "last spaced dash",
+	splitUnspaced:     "dash",
+	splitUnspacedMany: "last dash",
+	splitInserted:     "convention separator",
+	splitQuotes:       "title quotes",
+	splitKnown:        "known author",
+}
+
+// ExplainInfo extracts music info from the path like ExtractInfo
+// and traces the rules that extracted each field and tag.
+func ExplainInfo(filepath []byte) Explanation {
+	return ExplainPathInfo(SplitPath(filepath, PathAuto))
+}
+
+// ExplainPathInfo extracts music info from the path segments like
+// ExtractPathInfo and traces the rules that extracted each field and tag.
+func ExplainPathInfo(path [][]byte) Explanation {
+	a := analyzePath(path)
+	e := Explanation{Info: a.info}
+
+	for _, segment := range path {
+		e.Path = append(e.Path, string(segment))
+	}
+
+	if len(path) == 0 {
+		return e
+	}
+
+	file := len(path) - 1
+
+	for i, matches := range a.dirMatches {
+		e.traceTags(i, a.dirTexts[i], matches)
+	}
+
+	if n := len(a.dirnames); n > 0 && a.dir.Album != "" {
+		album, year := albumDirText(a.dirnames, a.file.Author)
+
+		rule := "author prefix"
+		if n >= 2 && strings.EqualFold(string(a.dirnames[n-2].trimSpace().b), foldString(a.file.Author)) {
+			rule = "author directory"
+		}
+
+		e.trace(rule, n-1, album, FieldAlbum, a.dir.Album)
+		if a.dir.Year != 0 {
+			e.trace("year prefix", n-1, year, FieldYear, strconv.Itoa(a.dir.Year))
+		}
+	}
+
+	if a.file.Track != 0 {
+		e.trace("track number", file, a.parse.track, FieldTrack, strconv.Itoa(a.file.Track))
+	}
+
+	rule := splitRules[a.parse.split]
+
+	if a.file.Author != "" {
+		e.trace(rule, file, a.parse.author, FieldAuthor, a.file.Author)
+	}
+	if a.file.Work != "" {
+		e.trace(rule, file, a.parse.work, FieldWork, a.file.Work)
+	}
+
+	e.traceTags(file, a.parse.tagText, a.parse.tags)
+	e.traceTags(file, a.parse.tagText, a.parse.cancelMatches)
+
+	return e
+}
+
+func (e *Explanation) trace(rule string, segment int, t text, field Field, value string) {
+	sp := t.source(0, len(t.b))
+
+	step := TraceStep{
+		Rule:    rule,
+		Segment: segment,
+		Start:   sp.start,
+		End:     sp.end,
+		Text:    t.String(),
+		Field:   field,
+		Value:   value,
+	}
+	if sp != literal {
+		step.Text = string(t.src[sp.start:sp.end])
+	}
+
+	e.Steps = append(e.Steps, step)
+}
+
+func (e *Explanation) traceTags(segment int, t text, matches []tagMatch) {
+	for _, m := range matches {
+		e.trace(m.rule, segment, t.slice(m.start, m.end), FieldTags, m.tag.String())
+
+		step := &e.Steps[len(e.Steps)-1]
+		step.Tag = m.tag
+		if m.deleted {
+			step.Change = ChangeDelete
+		}
+	}
+}
+
+// String formats the explanation as text for bug reports.
+func (e Explanation) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "path: %s\n", strings.Join(e.Path, "/"))
+	fmt.Fprintf(&sb, "info: %s\n", formatInfo(e.Info))
+
+	for _, s := range e.Steps {
+		fmt.Fprintf(&sb, "segment %d [%d:%d] %q: %s %s %q by %s\n",
+			s.Segment, s.Start, s.End, s.Text, s.Change, s.Field, s.Value, s.Rule)
+	}
+
+	return sb.String()
+}
+
+func formatInfo(info Info) string {
+	var fields []string
+
+	for f := FieldAuthor; f <= FieldTags; f++ {
+		if v := fieldValue(info, f); v != "" {
+			fields = append(fields, fmt.Sprintf("%s=%q", f, v))
+		}
+	}
+	if info.FileExtension != "" {
+		fields = append(fields, fmt.Sprintf("ext=%q", info.FileExtension))
+	}
+	if info.Kind != KindUnknown {
+		fields = append(fields, fmt.Sprintf("kind=%s", info.Kind))
+	}
+	if info.Repaired {
+		fields = append(fields, "repaired")
+	}
+
+	return strings.Join(fields, " ")
+}
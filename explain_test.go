@@ -0,0 +1,88 @@
+package musicfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplainInfo(t *testing.T) {
+	type args struct {
+		filepath string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []TraceStep
+	}{
+		{
+			name: "directory tags and album",
+			args: args{"Artist (Live at Wembley)/Artist - 1999 - Album/03. Artist - Title (Original Mix).mp3"},
+			want: []TraceStep{
+				{Rule: "live at", Segment: 0, Start: 8, End: 15, Text: "Live at", Field: FieldTags, Tag: Live, Value: "Live"},
+				{Rule: "keyword", Segment: 0, Start: 8, End: 12, Text: "Live", Field: FieldTags, Tag: Live, Value: "Live"},
+				{Rule: "author prefix", Segment: 1, Start: 16, End: 21, Text: "Album", Field: FieldAlbum, Value: "Album"},
+				{Rule: "year prefix", Segment: 1, Start: 9, End: 13, Text: "1999", Field: FieldYear, Value: "1999"},
+				{Rule: "track number", Segment: 2, Start: 0, End: 2, Text: "03", Field: FieldTrack, Value: "3"},
+				{Rule: "known author", Segment: 2, Start: 4, End: 10, Text: "Artist", Field: FieldAuthor, Value: "Artist"},
+				{Rule: "known author", Segment: 2, Start: 13, End: 18, Text: "Title", Field: FieldWork, Value: "Title"},
+				{Rule: "keyword", Segment: 2, Start: 29, End: 32, Text: "Mix", Field: FieldTags, Tag: Remix, Value: "Remix"},
+				{Rule: "original mix", Segment: 2, Start: 20, End: 32, Text: "Original Mix", Field: FieldTags, Tag: Remix, Change: ChangeDelete, Value: "Remix"},
+			},
+		},
+		{
+			name: "filename live",
+			args: args{"Artist - Title bootleg.mp3"},
+			want: []TraceStep{
+				{Rule: "spaced dash", Segment: 0, Start: 0, End: 6, Text: "Artist", Field: FieldAuthor, Value: "Artist"},
+				{Rule: "spaced dash", Segment: 0, Start: 9, End: 22, Text: "Title bootleg", Field: FieldWork, Value: "Title bootleg"},
+				{Rule: "filename live at", Segment: 0, Start: 15, End: 22, Text: "bootleg", Field: FieldTags, Tag: Live, Value: "Live"},
+			},
+		},
+		{
+			name: "weak keyword after convention",
+			args: args{"Some_Artist__Song (alt).mp3"},
+			want: []TraceStep{
+				{Rule: "convention separator", Segment: 0, Start: 0, End: 11, Text: "Some_Artist", Field: FieldAuthor, Value: "Some Artist"},
+				{Rule: "convention separator", Segment: 0, Start: 13, End: 17, Text: "Song", Field: FieldWork, Value: "Song"},
+				{Rule: "keyword", Segment: 0, Start: 19, End: 22, Text: "alt", Field: FieldTags, Tag: Remix, Value: "Remix"},
+			},
+		},
+		{
+			name: "studio cancels live",
+			args: args{"Bootlegs/Studio/Song.mp3"},
+			want: []TraceStep{
+				{Rule: "live at", Segment: 0, Start: 0, End: 7, Text: "Bootleg", Field: FieldTags, Tag: Live, Value: "Live"},
+				{Rule: "studio", Segment: 1, Start: 0, End: 6, Text: "Studio", Field: FieldTags, Tag: Live, Change: ChangeDelete, Value: "Live"},
+				{Rule: "whole name", Segment: 2, Start: 0, End: 4, Text: "Song", Field: FieldWork, Value: "Song"},
+			},
+		},
+		{
+			name: "not a track",
+			args: args{"Artist (Live)/cover.jpg"},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExplainInfo([]byte(tt.args.filepath)); !reflect.DeepEqual(got.Steps, tt.want) {
+				t.Errorf("ExplainInfo().Steps = %+v, want %+v", got.Steps, tt.want)
+			}
+		})
+	}
+}
+
+func TestExplanation_String(t *testing.T) {
+	got := ExplainInfo([]byte("Music/01. Artist - Title (Live).mp3")).String()
+
+	want := `path: Music/01. Artist - Title (Live).mp3
+info: author="Artist" work="Title" track="1" tags="Live" ext=".mp3" kind=audio
+segment 1 [0:2] "01": set track "1" by track number
+segment 1 [4:10] "Artist": set author "Artist" by spaced dash
+segment 1 [13:18] "Title": set work "Title" by spaced dash
+segment 1 [20:24] "Live": set tags "Live" by keyword
+`
+
+	if got != want {
+		t.Errorf("Explanation.String() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+package musicfile
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+)
+
+// Family is a container or codec family of an audio file.
+type Family string
+
+const (
+	FamilyMPEG     Family = "mpeg"
+	FamilyAAC      Family = "aac"
+	FamilyMP4      Family = "mp4"
+	FamilyFLAC     Family = "flac"
+	FamilyOgg      Family = "ogg"
+	FamilyOpus     Family = "opus"
+	FamilyWAV      Family = "wav"
+	FamilyAIFF     Family = "aiff"
+	FamilyAPE      Family = "ape"
+	FamilyWavPack  Family = "wavpack"
+	FamilyMusepack Family = "musepack"
+	FamilyTTA      Family = "tta"
+	FamilyWMA      Family = "wma"
+	FamilyDSD      Family = "dsd"
+	FamilyAC3      Family = "ac3"
+	FamilyDTS      Family = "dts"
+	FamilyMatroska Family = "matroska"
+	FamilyAMR      Family = "amr"
+	FamilyMIDI     Family = "midi"
+	FamilyTracker  Family = "tracker"
+)
+
+type extEntry struct {
+	kind   Kind
+	family Family
+	// temporary is a suffix of unfinished downloads and copies,
+	// like ".part" in "track.mp3.part".
+	temporary bool
+}
+
+var (
+	extensions   = map[string]extEntry{}
+	extensionsMu sync.RWMutex
+)
+
+func init() {
+	audio := map[Family][]string{
+		FamilyMPEG:     {".mp3", ".mp2", ".mp1", ".mpa"},
+		FamilyAAC:      {".aac", ".adts"},
+		FamilyMP4:      {".m4a", ".m4b", ".m4p", ".mp4", ".alac"},
+		FamilyFLAC:     {".flac", ".fla"},
+		FamilyOgg:      {".ogg", ".oga", ".spx"},
+		FamilyOpus:     {".opus"},
+		FamilyWAV:      {".wav", ".wave", ".w64"},
+		FamilyAIFF:     {".aif", ".aiff", ".aifc"},
+		FamilyAPE:      {".ape"},
+		FamilyWavPack:  {".wv"},
+		FamilyMusepack: {".mpc", ".mp+"},
+		FamilyTTA:      {".tta"},
+		FamilyWMA:      {".wma", ".asf"},
+		FamilyDSD:      {".dsf", ".dff"},
+		FamilyAC3:      {".ac3", ".eac3"},
+		FamilyDTS:      {".dts"},
+		FamilyMatroska: {".mka", ".weba", ".webm"},
+		FamilyAMR:      {".amr", ".awb"},
+		FamilyMIDI:     {".mid", ".midi", ".kar"},
+		FamilyTracker:  {".mod", ".xm", ".it", ".s3m"},
+	}
+
+	for family, exts := range audio {
+		for _, ext := range exts {
+			RegisterExtension(ext, family)
+		}
+	}
+
+	registerExtensions(KindPlaylist, ".m3u", ".m3u8", ".pls", ".xspf", ".wpl", ".asx")
+	registerExtensions(KindCuesheet, ".cue")
+	registerExtensions(KindRipLog, ".log", ".accurip")
+	registerExtensions(KindLyrics, ".lrc", ".elrc")
+	registerExtensions(KindImage, ".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".tif", ".tiff")
+	registerExtensions(KindOther,
+		".nfo", ".txt", ".sfv", ".md5", ".ffp", ".st5", ".pdf", ".db", ".ini", ".url", ".ds_store",
+	)
+
+	for _, ext := range []string{".part", ".partial", ".tmp", ".temp", ".crdownload", ".download", ".!ut"} {
+		extensions[ext] = extEntry{kind: KindOther, temporary: true}
+	}
+}
+
+// RegisterExtension registers the extension of audio files of the family,
+// e.g. ".mp3". The extension is lower-cased and a missing dot is added,
+// so "MP3" registers ".mp3" too. It is safe to call concurrently with
+// extraction.
+func RegisterExtension(ext string, family Family) {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext == "" || ext == "." {
+		return
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+
+	extensions[ext] = extEntry{kind: KindAudio, family: family}
+}
+
+func registerExtensions(kind Kind, exts ...string) {
+	for _, ext := range exts {
+		extensions[ext] = extEntry{kind: kind}
+	}
+}
+
+// splitExtension returns the index where the recognised extension
+// of the name begins, or the length of the name when there is none.
+// The extension may be compound, like ".flac.part". A temporary
+// extension alone, like ".part" in "Song.part", is cut off, but
+// the kind of the file is unknown.
+func splitExtension(name []byte) (i int, ext extEntry) {
+	i, ext, ok := lookupExtension(name)
+	if !ok {
+		return len(name), extEntry{}
+	}
+
+	if ext.temporary {
+		if j, inner, ok := lookupExtension(name[:i]); ok && !inner.temporary {
+			return j, inner
+		}
+		return i, extEntry{temporary: true}
+	}
+
+	return i, ext
+}
+
+// isTemporary tells if the name ends with the extension of an unfinished
+// download or copy, like "Song.mp3.part".
+func isTemporary(name []byte) bool {
+	_, ext, ok := lookupExtension(name)
+	return ok && ext.temporary
+}
+
+func lookupExtension(name []byte) (int, extEntry, bool) {
+	i := bytes.LastIndexByte(name, '.')
+	if i < 0 {
+		return 0, extEntry{}, false
+	}
+
+	extensionsMu.RLock()
+	ext, ok := extensions[strings.ToLower(strings.TrimSpace(string(name[i:])))]
+	extensionsMu.RUnlock()
+
+	return i, ext, ok
+}
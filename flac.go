@@ -0,0 +1,106 @@
+package musicfile
+
+import (
+	"io"
+)
+
+const (
+	flacBlockStreamInfo    = 0
+	flacBlockVorbisComment = 4
+)
+
+// ReadFLAC reads the Vorbis comment of a FLAC file.
+func ReadFLAC(r io.ReadSeeker) (Info, error) {
+	m, err := readFLAC(r)
+	if err != nil {
+		return Info{}, err
+	}
+	return m.info(), nil
+}
+
+func readFLAC(r io.ReadSeeker) (metadata, error) {
+	start, err := skipID3v2(r)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return metadata{}, err
+	}
+
+	var magic [4]byte
+
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return metadata{}, noMetadata(err)
+	}
+	if string(magic[:]) != "fLaC" {
+		return metadata{}, ErrNoMetadata
+	}
+
+	for {
+		var header [4]byte
+
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			return metadata{}, ErrMalformedMetadata
+		}
+
+		last := header[0]&0x80 != 0
+		kind := header[0] & 0x7F
+		size := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+
+		if kind == flacBlockVorbisComment {
+			data := make([]byte, size)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return metadata{}, ErrMalformedMetadata
+			}
+			return parseVorbisComment(data)
+		}
+
+		if last {
+			return metadata{}, ErrNoMetadata
+		}
+
+		if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+			return metadata{}, err
+		}
+	}
+}
+
+// skipID3v2 returns the offset of the data after the ID3v2 tag
+// that some taggers put before the FLAC stream.
+func skipID3v2(r io.ReadSeeker) (int64, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	var header [id3v2HeaderSize]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil
+	}
+	if string(header[:3]) != "ID3" {
+		return 0, nil
+	}
+
+	size, ok := syncsafe(header[6:10])
+	if !ok {
+		return 0, ErrMalformedMetadata
+	}
+
+	start := int64(id3v2HeaderSize + size)
+	if header[5]&0x10 != 0 {
+		// Footer.
+		start += id3v2HeaderSize
+	}
+
+	// Some file systems don't seek past the end.
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if start > end {
+		return 0, ErrMalformedMetadata
+	}
+
+	return start, nil
+}
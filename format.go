@@ -0,0 +1,312 @@
+package musicfile
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrTemplate is returned when the template of Format is invalid.
+var ErrTemplate = errors.New("invalid template")
+
+// ErrEmptyName is returned when Format makes a file name without a name
+// before the extension.
+var ErrEmptyName = errors.New("empty file name")
+
+// maxNameLength is the length of a path segment in bytes most file systems allow.
+const maxNameLength = 255
+
+// reservedNames are the device names Windows doesn't allow for files,
+// with any extension.
+var reservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true,
+	"com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true,
+	"lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// Format makes the file name of the info by the template, the reverse of
+// ExtractInfo. The template holds fields like "{author}" between literal
+// text:
+//
+//	{track:02} - {author} - {work}{tags: ({names})}{ext}
+//
+// The fields are author, album, work, track, disc, year, tags, ext, family
+// and kind. Tags are the names of the tags separated by commas, also
+// available as names. Numbers take the width to pad with zeros, like
+// "{track:02}". Any other text after a colon is written only when the field
+// is not empty, and may hold fields itself, like "{disc:CD{disc}/}".
+// Braces are escaped by doubling them.
+//
+// The missing fields are empty and the separators left around them are
+// dropped. Slashes of the template separate directories. The characters
+// POSIX and Windows don't allow in names are replaced with underscores,
+// empty directories are dropped.
+func Format(info Info, template string) (string, error) {
+	nodes, err := parseTemplate(template)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	expandTemplate(&b, nodes, info)
+
+	var segments []string
+
+	parts := strings.Split(b.String(), "/")
+
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			name, err := safeFileName(part)
+			if err != nil {
+				return "", err
+			}
+			segments = append(segments, name)
+			break
+		}
+
+		if dir := safeName(part, ""); dir != "" {
+			segments = append(segments, dir)
+		}
+	}
+
+	return strings.Join(segments, "/"), nil
+}
+
+// templateNode is literal text or a field with an optional
+// number width or conditional text.
+type templateNode struct {
+	literal string
+	field   string
+	width   int
+	text    []templateNode
+}
+
+func parseTemplate(template string) (nodes []templateNode, err error) {
+	var literal strings.Builder
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+
+		switch {
+		case (c == '{' || c == '}') && i+1 < len(template) && template[i+1] == c:
+			literal.WriteByte(c)
+			i++
+			continue
+		case c == '}':
+			return nil, fmt.Errorf("%w: unexpected '}' at %d", ErrTemplate, i)
+		case c != '{':
+			literal.WriteByte(c)
+			continue
+		}
+
+		end := closingBrace(template, i)
+		if end < 0 {
+			return nil, fmt.Errorf("%w: unclosed '{' at %d", ErrTemplate, i)
+		}
+
+		if literal.Len() > 0 {
+			nodes = append(nodes, templateNode{literal: literal.String()})
+			literal.Reset()
+		}
+
+		node, err := parseField(template[i+1 : end])
+		if err != nil {
+			return nil, err
+		}
+
+		nodes = append(nodes, node)
+		i = end
+	}
+
+	if literal.Len() > 0 {
+		nodes = append(nodes, templateNode{literal: literal.String()})
+	}
+
+	return nodes, nil
+}
+
+func parseField(s string) (node templateNode, err error) {
+	name, spec, conditional := strings.Cut(s, ":")
+
+	node.field = strings.TrimSpace(name)
+
+	if _, ok := fieldText(Info{}, node.field); !ok {
+		return node, fmt.Errorf("%w: unknown field %q", ErrTemplate, node.field)
+	}
+
+	if !conditional {
+		return node, nil
+	}
+
+	if isNumberField(node.field) && spec != "" && isNumber(spec) {
+		node.width, _ = strconv.Atoi(spec)
+		return node, nil
+	}
+
+	node.text, err = parseTemplate(spec)
+
+	return node, err
+}
+
+// closingBrace returns the index of the brace that closes the one at i.
+func closingBrace(s string, i int) int {
+	depth := 0
+
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func expandTemplate(b *strings.Builder, nodes []templateNode, info Info) {
+	for _, node := range nodes {
+		if node.field == "" {
+			b.WriteString(node.literal)
+			continue
+		}
+
+		value, _ := fieldText(info, node.field)
+		if value == "" {
+			continue
+		}
+
+		switch {
+		case node.text != nil:
+			expandTemplate(b, node.text, info)
+		case node.width > len(value):
+			b.WriteString(strings.Repeat("0", node.width-len(value)))
+			b.WriteString(value)
+		default:
+			// The values must not add directories.
+			b.WriteString(strings.NewReplacer("/", "_", `\`, "_").Replace(value))
+		}
+	}
+}
+
+// fieldText returns the text of the field of the info and whether
+// the field is known.
+func fieldText(info Info, field string) (string, bool) {
+	number := func(n int) string {
+		if n <= 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+
+	switch field {
+	case "author":
+		return info.Author, true
+	case "album":
+		return info.Album, true
+	case "work":
+		return info.Work, true
+	case "track":
+		return number(info.Track), true
+	case "disc":
+		return number(info.Disc), true
+	case "year":
+		return number(info.Year), true
+	case "tags", "names":
+		names := make([]string, bits)
+		n := info.Tags.Names(names)
+		return strings.Join(names[:n], ", "), true
+	case "ext":
+		return info.FileExtension, true
+	case "family":
+		return string(info.Family), true
+	case "kind":
+		if info.Kind == KindUnknown {
+			return "", true
+		}
+		return info.Kind.String(), true
+	}
+
+	return "", false
+}
+
+func isNumberField(field string) bool {
+	return field == "track" || field == "disc" || field == "year"
+}
+
+// safeFileName makes the file name safe, the extension is kept apart.
+func safeFileName(name string) (string, error) {
+	i, _ := splitExtension([]byte(name))
+
+	// Leave the stem room next to a long extension.
+	ext := truncateName(safeName(name[i:], ""), maxNameLength/2)
+	if strings.Trim(ext, ".") == "" {
+		ext = ""
+	}
+
+	stem := safeName(name[:i], ext)
+	if stem == "" {
+		return "", ErrEmptyName
+	}
+
+	return stem + ext, nil
+}
+
+// safeName replaces the characters POSIX and Windows don't allow in names
+// and tidies the separators. The result with the extension fits the
+// maximal name length.
+func safeName(name, ext string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r < 0x20 || r == 0x7F:
+			return -1
+		case strings.ContainsRune(`<>:"/\|?*`, r):
+			return '_'
+		}
+		return r
+	}, name)
+
+	name = strings.Join(strings.Fields(name), " ")
+	name = emptyBracketsRe.ReplaceAllString(name, "")
+	name = dashRunRe.ReplaceAllString(name, " - ")
+
+	for {
+		trimmed := strings.TrimSuffix(strings.TrimPrefix(name, "- "), " -")
+		// Windows drops the trailing dots and spaces.
+		trimmed = strings.TrimRight(strings.TrimSpace(trimmed), ". ")
+		if trimmed == "-" {
+			trimmed = ""
+		}
+		if trimmed == name {
+			break
+		}
+		name = trimmed
+	}
+
+	name = truncateName(name, max(maxNameLength-len(ext), 0))
+
+	if reservedNames[strings.ToLower(strings.SplitN(name, ".", 2)[0])] {
+		name += "_"
+	}
+
+	return name
+}
+
+// truncateName cuts the name to the limit in bytes on a rune boundary.
+func truncateName(name string, limit int) string {
+	if len(name) <= limit {
+		return name
+	}
+	for limit > 0 && !utf8.RuneStart(name[limit]) {
+		limit--
+	}
+	return strings.TrimRight(name[:limit], ". ")
+}
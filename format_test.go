@@ -0,0 +1,123 @@
+package musicfile
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	const template = "{track:02} - {author} - {work}{tags: ({names})}{ext}"
+
+	info := Info{
+		Author:        "Artist",
+		Album:         "Album",
+		Work:          "Song",
+		Track:         3,
+		Disc:          1,
+		Year:          1999,
+		Tags:          EmptyTags.Set(Live).Set(Remaster),
+		FileExtension: ".mp3",
+	}
+
+	type args struct {
+		info     Info
+		template string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr error
+	}{
+		{
+			name: "all fields",
+			args: args{info: info, template: template},
+			want: "03 - Artist - Song (Live, Remaster).mp3",
+		},
+		{
+			name: "missing fields",
+			args: args{info: Info{Work: "Song", FileExtension: ".flac"}, template: template},
+			want: "Song.flac",
+		},
+		{
+			name: "missing author",
+			args: args{info: Info{Work: "Song", Track: 12}, template: template},
+			want: "12 - Song",
+		},
+		{
+			name: "directories",
+			args: args{info: info, template: "{author}/{year:{year} - }{album}/{disc:CD{disc}/}{track:02}. {work}{ext}"},
+			want: "Artist/1999 - Album/CD1/03. Song.mp3",
+		},
+		{
+			name: "empty directories",
+			args: args{info: Info{Work: "Song"}, template: "{author}/{album}/{work}{ext}"},
+			want: "Song",
+		},
+		{
+			name: "unsafe characters",
+			args: args{info: Info{Author: "AC/DC", Work: `What? "Yes": <No> | *`, FileExtension: ".mp3"}, template: template},
+			want: "AC_DC - What_ _Yes__ _No_ _ _.mp3",
+		},
+		{
+			name: "trailing dots",
+			args: args{info: Info{Author: "R.E.M.", Work: "Song...", FileExtension: ".mp3"}, template: "{work}{ext}"},
+			want: "Song.mp3",
+		},
+		{
+			name: "reserved name",
+			args: args{info: Info{Work: "Con", FileExtension: ".mp3"}, template: "{work}{ext}"},
+			want: "Con_.mp3",
+		},
+		{
+			name: "long name",
+			args: args{info: Info{Work: strings.Repeat("я", 200), FileExtension: ".mp3"}, template: "{work}{ext}"},
+			want: strings.Repeat("я", 125) + ".mp3",
+		},
+		{
+			name: "long extension",
+			args: args{info: Info{Work: "Song", FileExtension: "." + strings.Repeat("a", 300)}, template: "{work}{ext}"},
+			want: "Song." + strings.Repeat("a", 250),
+		},
+		{
+			name: "escaped braces",
+			args: args{info: info, template: "{{{work}}}{ext}"},
+			want: "{Song}.mp3",
+		},
+		{
+			name:    "empty name",
+			args:    args{info: Info{FileExtension: ".mp3"}, template: template},
+			wantErr: ErrEmptyName,
+		},
+		{
+			name:    "unknown field",
+			args:    args{info: info, template: "{artist}"},
+			wantErr: ErrTemplate,
+		},
+		{
+			name:    "unclosed brace",
+			args:    args{info: info, template: "{work"},
+			wantErr: ErrTemplate,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.args.info, tt.args.template)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Format() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSafeName_longExtension(t *testing.T) {
+	ext := "." + strings.Repeat("a", 300)
+
+	if got := safeName("Song", ext); got != "" {
+		t.Errorf("safeName() = %q, want empty", got)
+	}
+}
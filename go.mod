@@ -3,3 +3,5 @@ module github.com/WinPooh32/music-file
 go 1.21
 
 require github.com/hedhyw/rex v0.6.0
+
+require golang.org/x/text v0.14.0
@@ -0,0 +1,441 @@
+package musicfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ErrNoMetadata is returned when the file has no embedded metadata.
+var ErrNoMetadata = errors.New("no embedded metadata found")
+
+// ErrMalformedMetadata is returned when the embedded metadata is broken.
+var ErrMalformedMetadata = errors.New("malformed embedded metadata")
+
+const (
+	id3v2HeaderSize = 10
+	id3v1Size       = 128
+)
+
+// id3Frames maps the ID3v2.2 and ID3v2.3/2.4 frame identifiers
+// to the fields of the metadata.
+var id3Frames = map[string]string{
+	"TP1": "artist", "TPE1": "artist",
+	"TP2": "albumartist", "TPE2": "albumartist",
+	"TT2": "title", "TIT2": "title",
+	"TT3": "version", "TIT3": "version",
+	"TAL": "album", "TALB": "album",
+	"TRK": "track", "TRCK": "track",
+	"TPA": "disc", "TPOS": "disc",
+	"TYE": "year", "TYER": "year", "TDRC": "year", "TDOR": "originalyear", "TORY": "originalyear",
+}
+
+// ReadID3 reads the ID3v2.2, ID3v2.3, ID3v2.4 and ID3v1 tags of an MP3 file.
+// The ID3v2 frames take precedence over the ID3v1 fields.
+func ReadID3(r io.ReadSeeker) (Info, error) {
+	m, err := readID3(r)
+	if err != nil {
+		return Info{}, err
+	}
+	return m.info(), nil
+}
+
+// ExtractMP3Info extracts music info from the file path and the ID3 tags
+// of the MP3 file. Non-empty embedded fields take precedence. The info of
+// the path is returned with the error when the tags can't be read.
+func ExtractMP3Info(filepath []byte, r io.ReadSeeker) (Info, error) {
+	info := ExtractInfo(filepath)
+
+	embedded, err := ReadID3(r)
+	if errors.Is(err, ErrNoMetadata) {
+		return info, nil
+	}
+	if err != nil {
+		return info, err
+	}
+
+	return mergeInfo(info, embedded), nil
+}
+
+func readID3(r io.ReadSeeker) (metadata, error) {
+	v2, err := readID3v2(r)
+	if err != nil && !errors.Is(err, ErrNoMetadata) {
+		return metadata{}, err
+	}
+
+	v1, err1 := readID3v1(r)
+	if err1 != nil && !errors.Is(err1, ErrNoMetadata) {
+		return metadata{}, err1
+	}
+
+	if err != nil && err1 != nil {
+		return metadata{}, ErrNoMetadata
+	}
+
+	return v2.fill(v1), nil
+}
+
+func readID3v2(r io.ReadSeeker) (m metadata, err error) {
+	_, frames, _, err := readID3v2Frames(r)
+	if err != nil {
+		return m, err
+	}
+
+	fields := metadataFields{}
+
+	for _, frame := range frames {
+		field, ok := id3Frames[frame.id]
+		if !ok || frame.data == nil {
+			continue
+		}
+
+		if _, dup := fields[field]; !dup {
+			fields[field] = decodeID3Text(frame.data)
+		}
+	}
+
+	return fields.metadata(), nil
+}
+
+// id3Frame is a frame of the ID3v2 tag. The data is nil
+// when the frame is compressed or encrypted.
+type id3Frame struct {
+	id   string
+	data []byte
+}
+
+// readID3v2Frames reads the frames of the ID3v2 tag at the start of the file.
+// It returns the size of the whole tag with its header and footer as well.
+func readID3v2Frames(r io.ReadSeeker) (version byte, frames []id3Frame, tagSize int64, err error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, nil, 0, err
+	}
+
+	var header [id3v2HeaderSize]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, 0, noMetadata(err)
+	}
+
+	if string(header[:3]) != "ID3" {
+		return 0, nil, 0, ErrNoMetadata
+	}
+
+	version, flags := header[3], header[5]
+	if version < 2 || version > 4 {
+		return 0, nil, 0, ErrNoMetadata
+	}
+
+	size, ok := syncsafe(header[6:10])
+	if !ok {
+		return 0, nil, 0, ErrMalformedMetadata
+	}
+
+	tagSize = int64(id3v2HeaderSize + size)
+	if version == 4 && flags&0x10 != 0 {
+		// Footer.
+		tagSize += id3v2HeaderSize
+	}
+
+	// Don't trust the size before allocating the body.
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	if int64(size) > end-id3v2HeaderSize {
+		return 0, nil, 0, ErrMalformedMetadata
+	}
+	if _, err := r.Seek(id3v2HeaderSize, io.SeekStart); err != nil {
+		return 0, nil, 0, err
+	}
+
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, 0, ErrMalformedMetadata
+	}
+
+	if flags&0x80 != 0 && version < 4 {
+		body = unsynchronize(body)
+	}
+
+	switch {
+	case version == 2 && flags&0x40 != 0:
+		// The compression of ID3v2.2 tags was never defined,
+		// the size is still returned to replace the tag.
+		return version, nil, tagSize, ErrNoMetadata
+	case flags&0x40 != 0:
+		body, err = skipExtendedHeader(body, version)
+		if err != nil {
+			return 0, nil, 0, err
+		}
+	}
+
+	for len(body) > 0 && body[0] != 0 {
+		var (
+			frame id3Frame
+			valid bool
+		)
+
+		frame.id, frame.data, body, valid = nextID3Frame(body, version, flags&0x80 != 0)
+		if !valid {
+			break
+		}
+
+		frames = append(frames, frame)
+	}
+
+	return version, frames, tagSize, nil
+}
+
+// nextID3Frame returns the identifier and the data of the first frame of
+// the tag body and the rest of the body. The data is nil when the frame
+// is compressed or encrypted.
+func nextID3Frame(body []byte, version byte, unsync bool) (id string, data, rest []byte, ok bool) {
+	if version == 2 {
+		if len(body) < 6 {
+			return "", nil, nil, false
+		}
+
+		size := int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		if size > len(body)-6 {
+			return "", nil, nil, false
+		}
+
+		return string(body[:3]), body[6 : 6+size], body[6+size:], true
+	}
+
+	if len(body) < 10 {
+		return "", nil, nil, false
+	}
+
+	var size int
+
+	if version == 4 {
+		n, ok := syncsafe(body[4:8])
+		if !ok {
+			return "", nil, nil, false
+		}
+		size = n
+	} else {
+		size = int(binary.BigEndian.Uint32(body[4:8]))
+	}
+
+	if size < 0 || size > len(body)-10 {
+		return "", nil, nil, false
+	}
+
+	id = string(body[:4])
+	format := body[9]
+	data = body[10 : 10+size]
+	rest = body[10+size:]
+
+	if version == 3 {
+		switch {
+		case format&0xC0 != 0:
+			// Compressed or encrypted.
+			return id, nil, rest, true
+		case format&0x20 != 0 && len(data) > 0:
+			// Grouping identity.
+			data = data[1:]
+		}
+		return id, data, rest, true
+	}
+
+	if format&0x0C != 0 {
+		// Compressed or encrypted.
+		return id, nil, rest, true
+	}
+	if format&0x40 != 0 && len(data) > 0 {
+		// Grouping identity.
+		data = data[1:]
+	}
+	if format&0x01 != 0 && len(data) >= 4 {
+		// Data length indicator.
+		data = data[4:]
+	}
+	if format&0x02 != 0 || unsync {
+		data = unsynchronize(data)
+	}
+
+	return id, data, rest, true
+}
+
+func skipExtendedHeader(body []byte, version byte) ([]byte, error) {
+	if len(body) < 4 {
+		return nil, ErrMalformedMetadata
+	}
+
+	var size int
+
+	if version == 4 {
+		// The size includes itself.
+		n, ok := syncsafe(body[:4])
+		if !ok {
+			return nil, ErrMalformedMetadata
+		}
+		size = n
+	} else {
+		size = int(binary.BigEndian.Uint32(body[:4])) + 4
+	}
+
+	if size < 0 || size > len(body) {
+		return nil, ErrMalformedMetadata
+	}
+
+	return body[size:], nil
+}
+
+func readID3v1(r io.ReadSeeker) (m metadata, err error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return m, err
+	}
+	if end < id3v1Size {
+		return m, ErrNoMetadata
+	}
+
+	if _, err := r.Seek(-id3v1Size, io.SeekEnd); err != nil {
+		return m, err
+	}
+
+	var tag [id3v1Size]byte
+
+	if _, err := io.ReadFull(r, tag[:]); err != nil {
+		return m, err
+	}
+
+	if string(tag[:3]) != "TAG" {
+		return m, ErrNoMetadata
+	}
+
+	m.title = decodeLatin1(trimID3v1(tag[3:33]))
+	m.artist = decodeLatin1(trimID3v1(tag[33:63]))
+	m.album = decodeLatin1(trimID3v1(tag[63:93]))
+	m.year = parseNumber(string(tag[93:97]))
+
+	// ID3v1.1 keeps the track number in the last byte of the comment.
+	if tag[125] == 0 && tag[126] != 0 {
+		m.track = int(tag[126])
+	}
+
+	return m, nil
+}
+
+func trimID3v1(field []byte) []byte {
+	if i := bytes.IndexByte(field, 0); i >= 0 {
+		field = field[:i]
+	}
+	return bytes.TrimRight(field, " ")
+}
+
+// decodeID3Text decodes the text frame data. Only the first
+// of the values separated by NUL is returned.
+func decodeID3Text(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	enc, data := data[0], data[1:]
+
+	var s string
+
+	switch enc {
+	case 0:
+		if i := bytes.IndexByte(data, 0); i >= 0 {
+			data = data[:i]
+		}
+		s = decodeLatin1(data)
+	case 1, 2:
+		s = decodeUTF16(data, enc == 2)
+	case 3:
+		if i := bytes.IndexByte(data, 0); i >= 0 {
+			data = data[:i]
+		}
+		s = string(bytes.ToValidUTF8(data, nil))
+	default:
+		return ""
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// decodeUTF16 decodes UTF-16 text with an optional byte order mark.
+func decodeUTF16(data []byte, bigEndian bool) string {
+	var order binary.ByteOrder = binary.LittleEndian
+
+	if bigEndian {
+		order = binary.BigEndian
+	}
+
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0xFF && data[1] == 0xFE:
+			order, data = binary.LittleEndian, data[2:]
+		case data[0] == 0xFE && data[1] == 0xFF:
+			order, data = binary.BigEndian, data[2:]
+		}
+	}
+
+	units := make([]uint16, 0, len(data)/2)
+
+	for i := 0; i+1 < len(data); i += 2 {
+		u := order.Uint16(data[i:])
+		if u == 0 {
+			break
+		}
+		units = append(units, u)
+	}
+
+	return string(utf16.Decode(units))
+}
+
+// decodeLatin1 decodes the text declared as ISO-8859-1. Many taggers
+// wrote the local code page or UTF-8 instead, so those are detected first.
+func decodeLatin1(data []byte) string {
+	if s, repaired := RepairEncoding(data); repaired {
+		return string(s)
+	}
+	if utf8.Valid(data) {
+		return string(data)
+	}
+
+	runes := make([]rune, len(data))
+	for i, c := range data {
+		runes[i] = rune(c)
+	}
+
+	return string(runes)
+}
+
+// unsynchronize reverts the ID3v2 unsynchronisation: "FF 00" becomes "FF".
+func unsynchronize(data []byte) []byte {
+	if !bytes.Contains(data, []byte{0xFF, 0x00}) {
+		return data
+	}
+
+	dst := make([]byte, 0, len(data))
+
+	for i := 0; i < len(data); i++ {
+		dst = append(dst, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0x00 {
+			i++
+		}
+	}
+
+	return dst
+}
+
+func syncsafe(b []byte) (int, bool) {
+	var n int
+	for _, c := range b {
+		if c&0x80 != 0 {
+			return 0, false
+		}
+		n = n<<7 | int(c)
+	}
+	return n, true
+}
@@ -0,0 +1,266 @@
+package musicfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestReadID3(t *testing.T) {
+	audio := []byte{0xFF, 0xFB, 0x90, 0x00, 0x00, 0x00}
+
+	tests := []struct {
+		name     string
+		file     []byte
+		wantInfo Info
+		wantErr  error
+	}{
+		{
+			name: "v2.3 utf-16",
+			file: concat(
+				testID3v2Tag(3, 0,
+					testID3Frame(3, "TPE1", utf16Text("Кино")),
+					testID3Frame(3, "TIT2", utf16Text("Звезда по имени Солнце (Live)")),
+					testID3Frame(3, "TALB", utf16Text("Звезда")),
+					testID3Frame(3, "TRCK", latin1Text("3/12")),
+					testID3Frame(3, "TPOS", latin1Text("1/2")),
+					testID3Frame(3, "TYER", latin1Text("1989")),
+				),
+				audio,
+			),
+			wantInfo: Info{
+				Author: "Кино",
+				Album:  "Звезда",
+				Work:   "Звезда по имени Солнце",
+				Track:  3,
+				Disc:   1,
+				Year:   1989,
+				Tags:   EmptyTags.Set(Live),
+			},
+		},
+		{
+			name: "v2.4 utf-8",
+			file: concat(
+				testID3v2Tag(4, 0,
+					testID3Frame(4, "TPE2", utf8Text("Artist")),
+					testID3Frame(4, "TIT2", utf8Text("Song")),
+					testID3Frame(4, "TIT3", utf8Text("Radio Edit")),
+					testID3Frame(4, "TDRC", utf8Text("2011-05-01")),
+				),
+				audio,
+			),
+			wantInfo: Info{
+				Author: "Artist",
+				Work:   "Song",
+				Year:   2011,
+				Tags:   EmptyTags.Set(Radio),
+			},
+		},
+		{
+			name: "v2.2 cp1251",
+			file: concat(
+				testID3v2Tag(2, 0,
+					testID3Frame(2, "TP1", append([]byte{0}, "\xc0\xf0\xe8\xff"...)),
+					testID3Frame(2, "TT2", latin1Text("Beyonc\xe9")),
+					testID3Frame(2, "TRK", latin1Text("7")),
+				),
+				audio,
+			),
+			wantInfo: Info{
+				Author: "Ария",
+				Work:   "Beyoncé",
+				Track:  7,
+			},
+		},
+		{
+			name: "v2.3 unsynchronised",
+			file: concat(
+				testID3v2Tag(3, 0x80,
+					testID3Frame(3, "APIC", []byte{0xFF, 0x00, 0xE0, 0xFF, 0x00}),
+					testID3Frame(3, "TIT2", latin1Text("Song")),
+				),
+				audio,
+			),
+			wantInfo: Info{
+				Work: "Song",
+			},
+		},
+		{
+			name: "v1.1",
+			file: concat(audio, testID3v1Tag("Song (Remix)", "Artist", "Album", "2001", 5)),
+			wantInfo: Info{
+				Author: "Artist",
+				Album:  "Album",
+				Work:   "Song",
+				Track:  5,
+				Year:   2001,
+				Tags:   EmptyTags.Set(Remix),
+			},
+		},
+		{
+			name: "v2 and v1",
+			file: concat(
+				testID3v2Tag(3, 0,
+					testID3Frame(3, "TIT2", latin1Text("Song")),
+				),
+				audio,
+				testID3v1Tag("Old", "Artist", "Album", "", 0),
+			),
+			wantInfo: Info{
+				Author: "Artist",
+				Album:  "Album",
+				Work:   "Song",
+			},
+		},
+		{
+			name:    "no tags",
+			file:    audio,
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "truncated",
+			file:    testID3v2Tag(3, 0, testID3Frame(3, "TIT2", latin1Text("Song")))[:15],
+			wantErr: ErrMalformedMetadata,
+		},
+		{
+			name:    "size past the end",
+			file:    []byte("ID3\x04\x00\x00\x7f\x7f\x7f\x7f"),
+			wantErr: ErrMalformedMetadata,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInfo, err := ReadID3(bytes.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadID3() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotInfo, tt.wantInfo) {
+				t.Errorf("ReadID3() = %+v, want %+v", gotInfo, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestExtractMP3Info(t *testing.T) {
+	file := testID3v2Tag(4, 0,
+		testID3Frame(4, "TIT2", utf8Text("Real Title")),
+		testID3Frame(4, "TRCK", utf8Text("2")),
+	)
+
+	gotInfo, err := ExtractMP3Info([]byte("Bootlegs/Artist - track02 (Live).mp3"), bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantInfo := Info{
+		Author:        "Artist",
+		Work:          "Real Title",
+		Track:         2,
+		Tags:          EmptyTags.Set(Live),
+		FileExtension: ".mp3",
+		Family:        FamilyMPEG,
+		Kind:          KindAudio,
+	}
+	if !reflect.DeepEqual(gotInfo, wantInfo) {
+		t.Errorf("ExtractMP3Info() = %+v, want %+v", gotInfo, wantInfo)
+	}
+}
+
+func TestExtractMP3Info_malformed(t *testing.T) {
+	file := []byte("ID3\x04\x00\x00\x7f\x7f\x7f\x7f")
+
+	gotInfo, err := ExtractMP3Info([]byte("Artist - Song (Live).mp3"), bytes.NewReader(file))
+	if !errors.Is(err, ErrMalformedMetadata) {
+		t.Fatalf("ExtractMP3Info() error = %v, wantErr %v", err, ErrMalformedMetadata)
+	}
+
+	wantInfo := Info{
+		Author:        "Artist",
+		Work:          "Song",
+		Tags:          EmptyTags.Set(Live),
+		FileExtension: ".mp3",
+		Family:        FamilyMPEG,
+		Kind:          KindAudio,
+	}
+	if !reflect.DeepEqual(gotInfo, wantInfo) {
+		t.Errorf("ExtractMP3Info() = %+v, want %+v", gotInfo, wantInfo)
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func latin1Text(s string) []byte {
+	return append([]byte{0}, s...)
+}
+
+func utf8Text(s string) []byte {
+	return append([]byte{3}, s...)
+}
+
+func utf16Text(s string) []byte {
+	b := []byte{1, 0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return append(b, 0, 0)
+}
+
+func testID3Frame(version byte, id string, data []byte) []byte {
+	b := []byte(id)
+
+	switch version {
+	case 2:
+		n := len(data)
+		b = append(b, byte(n>>16), byte(n>>8), byte(n))
+		return append(b, data...)
+	case 3:
+		b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	default:
+		b = append(b, testSyncsafe(len(data))...)
+	}
+
+	b = append(b, 0, 0)
+
+	return append(b, data...)
+}
+
+func testID3v2Tag(version, flags byte, frames ...[]byte) []byte {
+	body := concat(frames...)
+
+	if flags&0x80 != 0 {
+		var unsync []byte
+		for i, c := range body {
+			unsync = append(unsync, c)
+			if c == 0xFF && (i+1 == len(body) || body[i+1] == 0 || body[i+1]&0xE0 == 0xE0) {
+				unsync = append(unsync, 0)
+			}
+		}
+		body = unsync
+	}
+
+	b := []byte{'I', 'D', '3', version, 0, flags}
+	b = append(b, testSyncsafe(len(body))...)
+
+	return append(b, body...)
+}
+
+func testID3v1Tag(title, artist, album, year string, track byte) []byte {
+	b := make([]byte, id3v1Size)
+	copy(b, "TAG")
+	copy(b[3:33], title)
+	copy(b[33:63], artist)
+	copy(b[63:93], album)
+	copy(b[93:97], year)
+	b[126] = track
+	b[127] = 0xFF
+	return b
+}
+
+func testSyncsafe(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
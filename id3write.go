@@ -0,0 +1,226 @@
+package musicfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// id3Padding is left after the frames of a new tag, so the tag
+	// can be rewritten in place later.
+	id3Padding = 1024
+	// id3MaxSize is the largest size a syncsafe integer holds.
+	id3MaxSize = 1<<28 - 1
+)
+
+// ErrTagTooLarge is returned when the ID3v2 tag doesn't fit its size field.
+var ErrTagTooLarge = errors.New("id3 tag is too large")
+
+// id3v23Frames are converted to their ID3v2.4 replacements.
+var id3v23Frames = map[string]string{
+	"TYER": "TDRC",
+	"TORY": "TDOR",
+	// Dropped, there is no replacement.
+	"TDAT": "", "TIME": "", "TRDA": "", "TSIZ": "", "EQUA": "", "RVAD": "",
+}
+
+// EncodeID3v2 encodes the info into an ID3v2.4 tag of text frames: TPE1 from
+// the author, TIT2 from the work, TALB, TRCK, TPOS, TDRC from the year and
+// TIT3 from the tags, like "Live, Remaster". The empty fields are omitted.
+func EncodeID3v2(info Info) ([]byte, error) {
+	return encodeID3v2(id3InfoFrames(info), id3Padding)
+}
+
+// WriteID3 copies the MP3 file from src to dst with the ID3v2.4 tag made from
+// the info in place of the ID3v2 tag of the file. The frames of the old tag
+// are kept unless the info has their fields. The audio frames and the ID3v1
+// tag are copied as is.
+func WriteID3(dst io.Writer, src io.ReadSeeker, info Info) error {
+	frames, tagSize, err := updateID3Frames(src, info)
+	if err != nil {
+		return err
+	}
+
+	tag, err := encodeID3v2(frames, id3Padding)
+	if err != nil {
+		return err
+	}
+
+	if _, err := dst.Write(tag); err != nil {
+		return err
+	}
+
+	if _, err := src.Seek(tagSize, io.SeekStart); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dst, src)
+
+	return err
+}
+
+// WriteID3File writes the info into the ID3v2 tag of the MP3 file like
+// WriteID3. The tag is rewritten in place when it fits into the old tag
+// with its padding, otherwise the file is replaced by a rewritten copy.
+func WriteID3File(name string, info Info) error {
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	frames, tagSize, err := updateID3Frames(f, info)
+	if err != nil {
+		return err
+	}
+
+	// The padding is all that is left of the old tag.
+	if tagSize > id3v2HeaderSize {
+		tag, err := encodeID3v2(frames, 0)
+		if err != nil {
+			return err
+		}
+
+		if padding := tagSize - int64(len(tag)); padding >= 0 {
+			if tag, err = encodeID3v2(frames, int(padding)); err != nil {
+				return err
+			}
+			if _, err := f.WriteAt(tag, 0); err != nil {
+				return err
+			}
+			return f.Close()
+		}
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	err = WriteID3(tmp, f, info)
+	if err == nil {
+		err = tmp.Chmod(stat.Mode().Perm())
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	f.Close()
+
+	return os.Rename(tmp.Name(), name)
+}
+
+// updateID3Frames returns the frames of the info followed by the kept
+// frames of the old tag and the size of the old tag.
+func updateID3Frames(r io.ReadSeeker, info Info) ([]id3Frame, int64, error) {
+	version, old, tagSize, err := readID3v2Frames(r)
+	if err != nil && !errors.Is(err, ErrNoMetadata) {
+		return nil, 0, err
+	}
+
+	frames := id3InfoFrames(info)
+
+	written := make(map[string]bool, len(frames))
+	for _, frame := range frames {
+		written[frame.id] = true
+	}
+
+	// The frames of ID3v2.2 have other identifiers and layouts.
+	if version == 2 {
+		old = nil
+	}
+
+	for _, frame := range old {
+		if frame.data == nil {
+			// Compressed or encrypted.
+			continue
+		}
+
+		if id, ok := id3v23Frames[frame.id]; ok && version == 3 {
+			if id == "" {
+				continue
+			}
+			frame.id = id
+		}
+
+		if !written[frame.id] {
+			frames = append(frames, frame)
+		}
+	}
+
+	return frames, tagSize, nil
+}
+
+// id3InfoFrames returns the text frames of the non-empty fields of the info.
+func id3InfoFrames(info Info) (frames []id3Frame) {
+	add := func(id, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			frames = append(frames, id3Frame{id: id, data: append([]byte{3}, value...)})
+		}
+	}
+
+	number := func(n int) string {
+		if n <= 0 {
+			return ""
+		}
+		return strconv.Itoa(n)
+	}
+
+	names := make([]string, bits)
+	n := info.Tags.Names(names)
+
+	add("TPE1", info.Author)
+	add("TIT2", info.Work)
+	add("TALB", info.Album)
+	add("TRCK", number(info.Track))
+	add("TPOS", number(info.Disc))
+	add("TDRC", number(info.Year))
+	add("TIT3", strings.Join(names[:n], ", "))
+
+	return frames
+}
+
+// encodeID3v2 encodes the frames into an ID3v2.4 tag with the padding.
+func encodeID3v2(frames []id3Frame, padding int) ([]byte, error) {
+	size := padding
+	for _, frame := range frames {
+		size += id3v2HeaderSize + len(frame.data)
+	}
+
+	if size > id3MaxSize {
+		return nil, ErrTagTooLarge
+	}
+
+	tag := make([]byte, 0, id3v2HeaderSize+size)
+	tag = append(tag, "ID3\x04\x00\x00"...)
+	tag = appendSyncsafe(tag, size)
+
+	for _, frame := range frames {
+		tag = append(tag, frame.id...)
+		tag = appendSyncsafe(tag, len(frame.data))
+		tag = append(tag, 0, 0)
+		tag = append(tag, frame.data...)
+	}
+
+	return append(tag, make([]byte, padding)...), nil
+}
+
+func appendSyncsafe(b []byte, n int) []byte {
+	return append(b, byte(n>>21&0x7F), byte(n>>14&0x7F), byte(n>>7&0x7F), byte(n&0x7F))
+}
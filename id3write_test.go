@@ -0,0 +1,180 @@
+package musicfile
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeID3v2(t *testing.T) {
+	tests := []struct {
+		name string
+		info Info
+	}{
+		{
+			name: "all fields",
+			info: Info{
+				Author: "Кино",
+				Album:  "Album",
+				Work:   "Song",
+				Track:  3,
+				Disc:   2,
+				Year:   1988,
+				Tags:   EmptyTags.Set(Live).Set(Remaster),
+			},
+		},
+		{
+			name: "every tag",
+			info: Info{Work: "Song", Tags: ^EmptyTags},
+		},
+		{
+			name: "no fields",
+			info: Info{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tag, err := EncodeID3v2(tt.info)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := ReadID3(bytes.NewReader(tag))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.info) {
+				t.Errorf("ReadID3(EncodeID3v2()) = %+v, want %+v", got, tt.info)
+			}
+		})
+	}
+}
+
+func TestWriteID3(t *testing.T) {
+	audio := bytes.Repeat([]byte{0xFF, 0xFB, 0x90, 0x00}, 64)
+	v1 := testID3v1Tag("Old", "Old", "", "", 0)
+
+	src := concat(
+		testID3v2Tag(3, 0,
+			testID3Frame(3, "TIT2", latin1Text("Old")),
+			testID3Frame(3, "TYER", latin1Text("1999")),
+			testID3Frame(3, "TDAT", latin1Text("0101")),
+			testID3Frame(3, "COMM", latin1Text("eng\x00comment")),
+		),
+		audio,
+		v1,
+	)
+
+	var dst bytes.Buffer
+
+	info := Info{Author: "Artist", Work: "Song", Track: 1, Tags: EmptyTags.Set(Demo)}
+
+	if err := WriteID3(&dst, bytes.NewReader(src), info); err != nil {
+		t.Fatal(err)
+	}
+
+	version, frames, tagSize, err := readID3v2Frames(bytes.NewReader(dst.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 4 {
+		t.Errorf("version = %d, want 4", version)
+	}
+
+	var ids []string
+	for _, frame := range frames {
+		ids = append(ids, frame.id)
+	}
+	if want := []string{"TPE1", "TIT2", "TRCK", "TIT3", "TDRC", "COMM"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("frames = %v, want %v", ids, want)
+	}
+
+	if rest := dst.Bytes()[tagSize:]; !bytes.Equal(rest, concat(audio, v1)) {
+		t.Errorf("the audio frames and the ID3v1 tag are changed")
+	}
+
+	got, err := ReadID3(bytes.NewReader(dst.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := info
+	want.Year = 1999
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadID3() = %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteID3File(t *testing.T) {
+	audio := bytes.Repeat([]byte{0xFF, 0xFB, 0x90, 0x00}, 64)
+	info := Info{Author: "Artist", Work: "Song", Album: "Album"}
+
+	tests := []struct {
+		name        string
+		file        []byte
+		wantInPlace bool
+	}{
+		{
+			name: "padding",
+			file: concat(
+				testID3v2Tag(4, 0, testID3Frame(4, "TIT2", utf8Text("Old")), make([]byte, 256)),
+				audio,
+			),
+			wantInPlace: true,
+		},
+		{
+			name: "no room",
+			file: concat(
+				testID3v2Tag(4, 0, testID3Frame(4, "TIT2", utf8Text("Old"))),
+				audio,
+			),
+		},
+		{
+			name: "no tag",
+			file: audio,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "song.mp3")
+
+			if err := os.WriteFile(name, tt.file, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := WriteID3File(name, info); err != nil {
+				t.Fatal(err)
+			}
+
+			b, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if inPlace := len(b) == len(tt.file); inPlace != tt.wantInPlace {
+				t.Errorf("in place = %v, want %v", inPlace, tt.wantInPlace)
+			}
+			if !bytes.HasSuffix(b, audio) {
+				t.Errorf("the audio frames are changed")
+			}
+
+			got, err := ReadID3(bytes.NewReader(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, info) {
+				t.Errorf("ReadID3() = %+v, want %+v", got, info)
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("temporary files are left: %v", entries)
+			}
+		})
+	}
+}
@@ -2,162 +2,470 @@ package musicfile
 
 import (
 	"bytes"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type Info struct {
 	Author        string `json:"author,omitempty"`
 	Album         string `json:"album,omitempty"`
 	Work          string `json:"work,omitempty"`
+	Track         int    `json:"track,omitempty"`
+	Disc          int    `json:"disc,omitempty"`
+	Year          int    `json:"year,omitempty"`
 	Tags          Tags   `json:"tags,omitempty"`
 	FileExtension string `json:"file_extension,omitempty"`
+	Family        Family `json:"family,omitempty"`
+	// Kind of the file. Author, Work and Tags are only extracted
+	// for audio, lyrics and unknown files.
+	Kind Kind `json:"kind,omitempty"`
+	// Repaired is set when a path segment was converted from a legacy
+	// encoding or from mojibake, see RepairEncoding.
+	Repaired bool `json:"repaired,omitempty"`
 }
 
+// ExtractInfo extracts music info from the file path.
+// Both slashes and backslashes separate the path segments.
+// Use ExtractInfoStyle for POSIX names with backslashes.
 func ExtractInfo(filepath []byte) (info Info) {
+	return ExtractInfoStyle(filepath, PathAuto)
+}
+
+// ExtractInfoStyle extracts music info from the file path split with the style.
+func ExtractInfoStyle(filepath []byte, style PathStyle) (info Info) {
 	// Split the file path.
-	path := bytes.Split(filepath, []byte("/"))
+	path := SplitPath(filepath, style)
 	return ExtractPathInfo(path)
 }
 
+// ExtractLibraryInfo extracts music info from the path of a file of the
+// library at root. The root and its parents are left out, so a root like
+// "/mnt/Bootlegs" doesn't tag every file.
+func ExtractLibraryInfo(root, filepath []byte) (info Info) {
+	return ExtractPathInfo(SplitLibraryPath(root, filepath, PathAuto))
+}
+
 func ExtractPathInfo(path [][]byte) (info Info) {
+	return analyzePath(path).info
+}
+
+// extractPathSources extracts the info from the basename and
+// the info from the directories of the path separately.
+func extractPathSources(path [][]byte) (file, dir Info) {
+	a := analyzePath(path)
+	return a.file, a.dir
+}
+
+// pathAnalysis is the info extracted from a path with the details
+// of how it was parsed. The info is the info of the file name
+// completed by the info of the directories.
+type pathAnalysis struct {
+	info      Info
+	file, dir Info
+	parse     basenameParse
+	dirnames  []text
+	// dirTags and dirCancels are the tags found and canceled in each
+	// directory, dirMatches are the matches that found them and dirTexts
+	// are the names they were found in.
+	dirTags    []Tags
+	dirCancels []Tags
+	dirMatches [][]tagMatch
+	dirTexts   []text
+}
+
+func analyzePath(path [][]byte) (a pathAnalysis) {
 	if len(path) == 0 {
-		return Info{}
+		return a
+	}
+
+	a.dirnames = make([]text, len(path)-1)
+
+	for i := range a.dirnames {
+		var repaired bool
+		a.dirnames[i], repaired = prepare(path[i])
+		a.dir.Repaired = a.dir.Repaired || repaired
 	}
 
 	// Extract basename of the file.
 	basename := path[len(path)-1]
 
-	info.Author, info.Album, info.Work, info.Tags, info.FileExtension = processBasename(basename)
+	name, repaired := prepare(basename)
+
+	a.file, a.parse = processBasename(name, knownAuthors(a.dirnames))
+	a.file.Repaired = repaired
 
-	for i := 0; i < len(path)-1; i++ {
-		dirname := path[i]
-		tags := ExtractDirTags(dirname)
-		info.Tags = info.Tags.Append(tags)
+	if !a.file.Kind.describesTrack() {
+		a.dir = Info{}
+		a.info = a.file
+		return a
 	}
 
-	return info
-}
+	a.dirTags = make([]Tags, len(a.dirnames))
+	a.dirCancels = make([]Tags, len(a.dirnames))
+	a.dirMatches = make([][]tagMatch, len(a.dirnames))
+	a.dirTexts = make([]text, len(a.dirnames))
 
-func ExtractFilenameTags(filename []byte) (tags Tags) {
-	if tagsFilenameLiveAtRe.Match(filename) {
-		tags = tags.Set(Live)
+	for i, dirname := range a.dirnames {
+		a.dirTexts[i] = spaceConvention(dirname)
+
+		var cancels []tagMatch
+		a.dirTags[i], a.dirMatches[i] = matchTags(a.dirTexts[i].b, tagsLiveAtRe)
+		a.dirCancels[i], cancels = matchCancels(a.dirTexts[i].b, true)
+		a.dirMatches[i] = append(a.dirMatches[i], cancels...)
 	}
-	if tagsInterviewWithRe.Match(filename) {
-		tags = tags.Set(Interview)
+
+	// The nearer segments and the file name cancel the tags of the farther ones.
+	a.dir.Tags = inheritTags(a.dirTags, a.dirCancels) &^ a.parse.cancels
+
+	a.dir.Album, a.dir.Year = albumDir(a.dirnames, a.file.Author)
+
+	a.info = a.file
+	a.info.Tags = a.info.Tags.Append(a.dir.Tags)
+	a.info.Repaired = a.info.Repaired || a.dir.Repaired
+	if a.info.Album == "" {
+		a.info.Album = a.dir.Album
 	}
-	if tagsCoverBy.Match(filename) {
-		tags = tags.Set(Cover)
+	if a.info.Year == 0 {
+		a.info.Year = a.dir.Year
 	}
 
-	tags = tags.Append(extractParenthesesTags(filename))
+	return a
+}
+
+// knownAuthors returns the authors the directories may name: the names of
+// the parent and the grandparent, and the beginnings of the parent name
+// before spaced dashes, like "Artist" of "Artist - Album". The longest go first.
+func knownAuthors(dirnames []text) (authors []string) {
+	n := len(dirnames)
 
-	if tagsOriginalMixRe.Match(filename) {
-		tags = tags.Del(Remix)
+	if n >= 2 {
+		authors = append(authors, string(dirnames[n-2].trimSpace().b))
 	}
-	if tagsMixBy.Match(filename) {
-		tags = tags.Set(Remix)
+
+	if n >= 1 {
+		parent := dirnames[n-1].trimSpace().b
+
+		authors = append(authors, string(parent))
+
+		for _, loc := range spacedDashRe.FindAllIndex(parent, -1) {
+			authors = append(authors, string(parent[:loc[0]]))
+		}
 	}
 
-	return tags
+	sort.SliceStable(authors, func(i, j int) bool {
+		return len(authors[i]) > len(authors[j])
+	})
+
+	return authors
 }
 
-func ExtractDirTags(dirname []byte) (tags Tags) {
-	if tagsLiveAtRe.Match(dirname) {
-		tags = tags.Set(Live)
+// albumDir returns the album and the year the parent directory names,
+// like "Album" in "Artist/Album" and "Artist - 1999 - Album".
+func albumDir(dirnames []text, author string) (album string, year int) {
+	albumText, yearText := albumDirText(dirnames, author)
+	year, _ = strconv.Atoi(string(yearText.b))
+	return albumText.String(), year
+}
+
+// albumDirText returns the parts of the parent directory name
+// that are the album and the year.
+func albumDirText(dirnames []text, author string) (album, year text) {
+	n := len(dirnames)
+	if n == 0 || author == "" {
+		return album, year
 	}
-	if tagsInterviewWithRe.Match(dirname) {
-		tags = tags.Set(Interview)
+
+	author = foldString(author)
+	parent := dirnames[n-1].trimSpace()
+
+	switch {
+	case strings.EqualFold(string(parent.b), author):
+		return album, year
+	case n >= 2 && strings.EqualFold(string(dirnames[n-2].trimSpace().b), author):
+	default:
+		i := cutAuthor(parent.b, author)
+		if i < 0 {
+			return album, year
+		}
+		parent = parent.slice(i, len(parent.b))
 	}
-	if tagsCoverBy.Match(dirname) {
-		tags = tags.Set(Cover)
+
+	if m := yearPrefixRe.FindSubmatchIndex(parent.b); m != nil && m[1] < len(parent.b) {
+		year = parent.slice(m[2], m[3])
+		parent = parent.slice(m[1], len(parent.b))
 	}
 
-	tags = tags.Append(extractParenthesesTags(dirname))
+	return parent.trimSpace(), year
+}
 
-	if tagsOriginalMixRe.Match(dirname) {
-		tags = tags.Del(Remix)
+// cutAuthor returns the index after the author and the dash that follow it
+// at the beginning of the name, or -1 if the name doesn't begin so.
+func cutAuthor(name []byte, author string) int {
+	if len(author) == 0 || len(name) <= len(author) || !strings.EqualFold(string(name[:len(author)]), author) {
+		return -1
 	}
-	if tagsMixBy.Match(dirname) {
-		tags = tags.Set(Remix)
+
+	loc := dashSeparatorRe.FindIndex(name[len(author):])
+	if loc == nil || len(author)+loc[1] == len(name) {
+		return -1
 	}
 
+	return len(author) + loc[1]
+}
+
+func ExtractFilenameTags(filename []byte) (tags Tags) {
+	name, _ := prepare(filename)
+	return filenameTags(filenameConvention(name).b)
+}
+
+func ExtractDirTags(dirname []byte) (tags Tags) {
+	name, _ := prepare(dirname)
+	return dirTags(spaceConvention(name).b)
+}
+
+// prepare repairs the encoding of the path segment and normalizes it.
+func prepare(segment []byte) (name text, repaired bool) {
+	segment, repaired = RepairEncoding(segment)
+	return normalize(segment), repaired
+}
+
+func filenameTags(filename []byte) (tags Tags) {
+	tags, _ = matchTags(filename, tagsFilenameLiveAtRe)
+	return tags
+}
+
+func dirTags(dirname []byte) (tags Tags) {
+	tags, _ = matchTags(dirname, tagsLiveAtRe)
 	return tags
 }
 
-func processBasename(name []byte) (author, album, work string, tags Tags, fileExtension string) {
+// basenameParse tells how the name of the file was parsed.
+type basenameParse struct {
+	// stem is the name without the extension and the brackets.
+	stem text
+	// tagText is the name the tags were found in.
+	tagText text
+	tags    []tagMatch
+	// cancels are the tags of the directories the name cancels.
+	cancels       Tags
+	cancelMatches []tagMatch
+	// The parts of the stem that became the fields.
+	author, work, track text
+	split               splitKind
+	quoted              bool
+	// trackSeparated is set when a dot or a dash follows the track number.
+	trackSeparated bool
+}
+
+// splitKind is the way the author and the work were split.
+type splitKind int
+
+const (
+	splitNone splitKind = iota
+	// splitDash is the single spaced dash, like "Artist - Title".
+	splitDash
+	// splitDashes is one of several spaced dashes, like "a - b - c".
+	splitDashes
+	// splitUnspaced is the single dash without spaces, like "Artist-Title".
+	splitUnspaced
+	// splitUnspacedMany is one of several dashes without spaces.
+	splitUnspacedMany
+	// splitInserted is the dash inserted by a naming convention,
+	// like "Artist__Title" and "John Smith Title".
+	splitInserted
+	// splitQuotes is the Japanese "author「work」" form.
+	splitQuotes
+	// splitKnown is the author named by the directories.
+	splitKnown
+)
+
+// basenameStem returns the extension, the kind and the tags of the file
+// and its name without the extension and the content of the brackets.
+func basenameStem(name text) (info Info, stem text, parse basenameParse) {
 	// Exclude file extension.
-	if i := bytes.LastIndexByte(name, '.'); i >= 0 {
-		fileExtension = string(name[i:])
-		name = name[0:i]
-	}
+	i, ext := splitExtension(name.b)
 
-	fileExtension = strings.TrimSpace(fileExtension)
+	info.FileExtension = strings.TrimSpace(name.slice(i, len(name.b)).String())
+	info.Family = ext.family
+	info.Kind = ext.kind
 
-	if fileExtension == "" {
-		fileExtension = "."
+	if !info.Kind.describesTrack() {
+		return info, name, parse
 	}
 
+	name = filenameConvention(name.slice(0, i))
+
 	// Fill info struct.
 
-	tags = ExtractFilenameTags(name)
+	info.Tags, parse.tags = matchTags(name.b, tagsFilenameLiveAtRe)
+	parse.cancels, parse.cancelMatches = matchCancels(name.b, false)
+	parse.tagText = name
 
 	// Delete all parentheses's content.
-	for parenthesesRe.Match(name) {
-		name = parenthesesRe.ReplaceAll(name, []byte{})
+	for parenthesesRe.Match(name.b) {
+		name = name.deleteAll(parenthesesRe)
 	}
 
-	if bytes.ContainsRune(name, '(') {
-		name = bytes.ReplaceAll(name, []byte{'('}, []byte{})
+	// Delete unpaired opening brackets.
+	for _, r := range openBrackets {
+		name = name.deleteRune(r)
 	}
 
-	if bytes.ContainsRune(name, '[') {
-		name = bytes.ReplaceAll(name, []byte{'['}, []byte{})
+	parse.quoted = titleQuotesRe.Match(name.b)
+
+	return info, unquoteTitle(name), parse
+}
+
+// processBasename extracts the info from the name of the file. The known
+// authors split the author and the work when both may have dashes.
+func processBasename(name text, authors []string) (info Info, parse basenameParse) {
+	info, name, parse = basenameStem(name)
+
+	if !info.Kind.describesTrack() {
+		return info, parse
 	}
 
-	subexpNames := infoFilenameRe.SubexpNames()
+	parse.stem = name
 
-	for _, match := range infoFilenameRe.FindAllSubmatch(name, -1) {
-		for groupIdx, group := range match {
-			if groupIdx == 0 || len(group) == 0 {
-				continue
-			}
-			groupName := subexpNames[groupIdx]
-			if groupName == "" {
-				continue
-			}
+	re := infoFilenameRe
+	if spacedDashRe.Match(name.b) {
+		re = infoFilenameSpacedRe
+	}
 
-			switch groupName {
-			case groupAuthor, groupWork:
-			default:
+	subexpNames := re.SubexpNames()
+
+	for _, match := range re.FindAllSubmatchIndex(name.b, -1) {
+		authorStart, authorEnd, trackEnd := -1, -1, -1
+
+		for groupIdx := 1; groupIdx < len(match)/2; groupIdx++ {
+			start, end := match[2*groupIdx], match[2*groupIdx+1]
+			if start < 0 || start == end {
 				continue
 			}
 
-			s := string(group)
-			s = strings.TrimSpace(s)
-
-			switch groupName {
+			switch subexpNames[groupIdx] {
+			case groupTrack:
+				// Longer numbers are years and catalogue numbers.
+				if end-start <= 3 {
+					info.Track, _ = strconv.Atoi(string(name.b[start:end]))
+					parse.track = name.slice(start, end)
+					trackEnd = end
+				}
 			case groupAuthor:
-				author = s
+				authorStart, authorEnd = start, end
+				parse.author = name.slice(start, end).trimSpace()
+				info.Author = parse.author.String()
 			case groupWork:
-				work = s
+				if trackEnd >= 0 {
+					sepEnd := start
+					if authorStart >= 0 {
+						sepEnd = authorStart
+					}
+					parse.trackSeparated = bytes.ContainsAny(name.b[trackEnd:sepEnd], "."+dashes)
+				}
+				if authorStart >= 0 {
+					// Split the author and the work again by the known author.
+					whole := name.slice(authorStart, end)
+					if author, work, ok := splitKnownAuthor(whole, authors); ok {
+						parse.author, parse.work = author, work
+						info.Author, info.Work = author.String(), work.String()
+						parse.split = splitKnown
+						continue
+					}
+					parse.split = splitKindOf(name, re, authorEnd, start, parse.quoted)
+				}
+				parse.work = name.slice(start, end).trimSpace()
+				info.Work = parse.work.String()
 			}
 		}
 	}
 
-	if work == "" {
-		s := string(name)
-		s = strings.TrimSpace(s)
-		work = string(s)
+	if info.Work == "" {
+		parse.work = name.trimSpace()
+		info.Work = parse.work.String()
 	}
 
-	return author, album, work, tags, fileExtension
+	return info, parse
 }
 
-func extractParenthesesTags(name []byte) (tags Tags) {
-	for _, match := range parenthesesRe.FindAll(name, -1) {
-		tags = tags.Append(extractTagsByRegexp(match))
+// splitKindOf tells the kind of the separator between the author and the work.
+func splitKindOf(name text, re *regexp.Regexp, start, end int, quoted bool) splitKind {
+	for i := start; i < end; i++ {
+		if name.from[i] != literal || name.b[i] == ' ' {
+			continue
+		}
+		if quoted {
+			return splitQuotes
+		}
+		return splitInserted
 	}
-	return tags
+
+	if re == infoFilenameSpacedRe {
+		if len(spacedDashRe.FindAllIndex(name.b, 2)) > 1 {
+			return splitDashes
+		}
+		return splitDash
+	}
+
+	if len(dashRe.FindAllIndex(name.b, 2)) > 1 {
+		return splitUnspacedMany
+	}
+	return splitUnspaced
+}
+
+// splitKnownAuthor splits the name that begins with one of the authors.
+func splitKnownAuthor(name text, authors []string) (author, work text, ok bool) {
+	for _, a := range authors {
+		i := cutAuthor(name.b, a)
+		if i < 0 {
+			continue
+		}
+		author = name.slice(0, len(a)).trimSpace()
+		work = name.slice(i, len(name.b)).trimSpace()
+		return author, work, true
+	}
+	return text{}, text{}, false
+}
+
+// unquoteTitle turns the Japanese "author「work」" form into "author - work".
+func unquoteTitle(name text) text {
+	loc := titleQuotesRe.FindIndex(name.b)
+	if loc == nil {
+		return name
+	}
+
+	author := name.slice(0, loc[0]).trimSpace()
+
+	work := name.slice(loc[0], loc[1])
+	for len(work.b) > 0 {
+		r, size := utf8.DecodeRune(work.b)
+		if !isTitleQuote(r) {
+			break
+		}
+		work = work.slice(size, len(work.b))
+	}
+	for len(work.b) > 0 {
+		r, size := utf8.DecodeLastRune(work.b)
+		if !isTitleQuote(r) {
+			break
+		}
+		work = work.slice(0, len(work.b)-size)
+	}
+
+	dst := name.slice(0, 0)
+
+	if len(author.b) > 0 {
+		dst = dst.append(author).appendLiteral(" - ")
+	}
+
+	return dst.append(work).append(name.slice(loc[1], len(name.b)))
+}
+
+func isTitleQuote(r rune) bool {
+	return strings.ContainsRune(openTitleQuotes, r) || strings.ContainsRune(closeTitleQuotes, r)
 }
 
 func extractTagsByRegexp(name []byte) (tags Tags) {
@@ -20,7 +20,7 @@ func TestExtractInfo(t *testing.T) {
 			args: args{
 				filepath: []byte("/"),
 			},
-			wantInfo: Info{FileExtension: "."},
+			wantInfo: Info{},
 		},
 		{
 			name: "complex live 1",
@@ -33,6 +33,8 @@ func TestExtractInfo(t *testing.T) {
 				Work:          "work",
 				Tags:          EmptyTags.Set(Live),
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -46,6 +48,8 @@ func TestExtractInfo(t *testing.T) {
 				Work:          "work",
 				Tags:          EmptyTags.Set(Live),
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -59,6 +63,8 @@ func TestExtractInfo(t *testing.T) {
 				Work:          "work",
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -72,6 +78,8 @@ func TestExtractInfo(t *testing.T) {
 				Work:          "work live work",
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -85,6 +93,8 @@ func TestExtractInfo(t *testing.T) {
 				Work:          "a",
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -96,8 +106,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "author",
 				Album:         "",
 				Work:          "work",
+				Track:         3,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -109,8 +122,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "author",
 				Album:         "",
 				Work:          "work",
+				Track:         3,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -122,8 +138,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "author",
 				Album:         "",
 				Work:          "work",
+				Track:         3,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -135,8 +154,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "author",
 				Album:         "",
 				Work:          "work",
+				Track:         1,
 				Tags:          EmptyTags.Set(Remix),
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -148,8 +170,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "",
 				Album:         "",
 				Work:          "work name",
+				Track:         2,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -161,8 +186,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "",
 				Album:         "",
 				Work:          "work name",
+				Track:         2,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -174,8 +202,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "",
 				Album:         "",
 				Work:          "work name",
+				Track:         2,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -187,8 +218,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "author",
 				Album:         "",
 				Work:          "work name",
+				Track:         3,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -200,8 +234,11 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "author",
 				Album:         "",
 				Work:          "work name",
+				Track:         3,
 				Tags:          EmptyTags,
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 		{
@@ -213,8 +250,545 @@ func TestExtractInfo(t *testing.T) {
 				Author:        "author",
 				Album:         "",
 				Work:          "work name",
+				Track:         3,
 				Tags:          EmptyTags.Set(Live),
 				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "upper case tags",
+			args: args{
+				filepath: []byte("Music/Artist - Title (LIVE).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Album:         "",
+				Work:          "Title",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "upper case cyrillic tags",
+			args: args{
+				filepath: []byte("ДДТ - Концерт в Москве/01. ДДТ - Осень (РЕМИКС).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "ДДТ",
+				Album:         "Концерт в Москве",
+				Work:          "Осень",
+				Track:         1,
+				Tags:          EmptyTags.Set(Live).Set(Remix),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "decomposed and dashes",
+			args: args{
+				filepath: []byte("\u0415\u0308лка\u00a0\u2013 Мои\u0306 путь (живои\u0306 концерт).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "\u0415\u0308лка",
+				Album:         "",
+				Work:          "Мои\u0306 путь",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "em dash and hyphenated author",
+			args: args{
+				filepath: []byte("Би-2 — Полковнику никто не пишет.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Би-2",
+				Album:         "",
+				Work:          "Полковнику никто не пишет",
+				Tags:          EmptyTags,
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "underscores",
+			args: args{
+				filepath: []byte("Live_at_Wembley/Artist_-_Title_(Live).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Album:         "",
+				Work:          "Title",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "underscores without dash",
+			args: args{
+				filepath: []byte("01_artist_title_(remix).ogg"),
+			},
+			wantInfo: Info{
+				Author:        "artist",
+				Album:         "",
+				Work:          "title",
+				Track:         1,
+				Tags:          EmptyTags.Set(Remix),
+				FileExtension: ".ogg",
+				Family:        FamilyOgg,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "dots",
+			args: args{
+				filepath: []byte("artist.-.title.flac"),
+			},
+			wantInfo: Info{
+				Author:        "artist",
+				Album:         "",
+				Work:          "title",
+				Tags:          EmptyTags,
+				FileExtension: ".flac",
+				Family:        FamilyFLAC,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "dots with initials",
+			args: args{
+				filepath: []byte("B.B.King.feat.Eric.Clapton.-.The.Thrill.Is.Gone.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "B.B. King feat. Eric Clapton",
+				Album:         "",
+				Work:          "The Thrill Is Gone",
+				Tags:          EmptyTags,
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "dotted abbreviation",
+			args: args{
+				filepath: []byte("Mr. Big - To Be With You.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Mr. Big",
+				Album:         "",
+				Work:          "To Be With You",
+				Tags:          EmptyTags,
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "drive-like author",
+			args: args{
+				filepath: []byte(`A:Side - Song.mp3`),
+			},
+			wantInfo: Info{
+				Author:        "A:Side",
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "windows drive",
+			args: args{
+				filepath: []byte(`D:\Music\Artist\Live at Wembley\01 - Artist - Song.mp3`),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Album:         "Live at Wembley",
+				Work:          "Song",
+				Track:         1,
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "unc share",
+			args: args{
+				filepath: []byte(`\\live\bootlegs\Song.mp3`),
+			},
+			wantInfo: Info{
+				Author:        "",
+				Album:         "",
+				Work:          "Song",
+				Tags:          EmptyTags,
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "cp1251",
+			args: args{
+				filepath: []byte("\xc0\xf0\xe8\xff - \xd8\xf2\xe8\xeb\xfc (\xea\xee\xed\xf6\xe5\xf0\xf2).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Ария",
+				Album:         "",
+				Work:          "Штиль",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+				Repaired:      true,
+			},
+		},
+		{
+			name: "mojibake directory",
+			args: args{
+				filepath: []byte("Êèíî (Æèâîé êîíöåðò)/Song.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "",
+				Album:         "",
+				Work:          "Song",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+				Repaired:      true,
+			},
+		},
+		{
+			name: "unknown extension",
+			args: args{
+				filepath: []byte("Mr. Big"),
+			},
+			wantInfo: Info{
+				Author:        "",
+				Album:         "",
+				Work:          "Mr. Big",
+				Tags:          EmptyTags,
+				FileExtension: "",
+			},
+		},
+		{
+			name: "partial download",
+			args: args{
+				filepath: []byte("Artist - Song (Live).FLAC.part"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Album:         "",
+				Work:          "Song",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".FLAC.part",
+				Family:        FamilyFLAC,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "partial download without extension",
+			args: args{
+				filepath: []byte("Artist - Song.part"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				FileExtension: ".part",
+			},
+		},
+		{
+			name: "opus",
+			args: args{
+				filepath: []byte("Artist - Song.opus"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Album:         "",
+				Work:          "Song",
+				Tags:          EmptyTags,
+				FileExtension: ".opus",
+				Family:        FamilyOpus,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "fullwidth brackets",
+			args: args{
+				filepath: []byte("アニソン/01. author － work【ライブ】（オフボーカル）.flac"),
+			},
+			wantInfo: Info{
+				Author:        "author",
+				Album:         "",
+				Work:          "work",
+				Track:         1,
+				Tags:          EmptyTags.Set(Live).Set(Instrumental),
+				FileExtension: ".flac",
+				Family:        FamilyFLAC,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "title quotes",
+			args: args{
+				filepath: []byte("02 LiSA「紅蓮華」(TV Size).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "LiSA",
+				Album:         "",
+				Work:          "紅蓮華",
+				Track:         2,
+				Tags:          EmptyTags.Set(Fragment),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "chinese accompaniment",
+			args: args{
+				filepath: []byte("周杰伦 - 晴天 (伴奏).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "周杰伦",
+				Album:         "",
+				Work:          "晴天",
+				Tags:          EmptyTags.Set(BackingTrack),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "dash in work",
+			args: args{
+				filepath: []byte("05 - Jay-Z - Night-Time.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Jay-Z",
+				Work:          "Night-Time",
+				Track:         5,
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "number in author",
+			args: args{
+				filepath: []byte("10cc - Dreadlock Holiday.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "10cc",
+				Work:          "Dreadlock Holiday",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "known author",
+			args: args{
+				filepath: []byte("Artist/Album/01 - Artist - Part One - Part Two.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Album:         "Album",
+				Work:          "Part One - Part Two",
+				Track:         1,
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "known author with dash",
+			args: args{
+				filepath: []byte("Сектор Газа - Live - 1997 - Альбом/02. Сектор Газа - Live - Песня.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Сектор Газа - Live",
+				Album:         "Альбом",
+				Work:          "Песня",
+				Track:         2,
+				Year:          1997,
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "underscore directory is not split",
+			args: args{
+				filepath: []byte("Pearl_Alive/Song.mp3"),
+			},
+			wantInfo: Info{
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "underscore title without track is not split",
+			args: args{
+				filepath: []byte("Hey_Jude.mp3"),
+			},
+			wantInfo: Info{
+				Work:          "Hey Jude",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "dotted initials without track are not split",
+			args: args{
+				filepath: []byte("B.B.King.mp3"),
+			},
+			wantInfo: Info{
+				Work:          "B.B. King",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "nearer studio cancels live",
+			args: args{
+				filepath: []byte("Live Bootlegs/1993 (Studio Demos)/01. Song.mp3"),
+			},
+			wantInfo: Info{
+				Work:          "Song",
+				Track:         1,
+				Tags:          EmptyTags.Set(Demo),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "album version cancels directory tags",
+			args: args{
+				filepath: []byte("Bootlegs/Artist - Song (Album Version).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "nearer live overrides studio",
+			args: args{
+				filepath: []byte("Bootlegs/Studio/Artist - Song (Live).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "nearer unbracketed studio demos cancel live",
+			args: args{
+				filepath: []byte("Live Bootlegs/1993 Studio Demos/01. Song.mp3"),
+			},
+			wantInfo: Info{
+				Work:          "Song",
+				Track:         1,
+				Tags:          EmptyTags.Set(Demo),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "original in a title doesn't cancel",
+			args: args{
+				filepath: []byte("Covers (Tribute)/Artist - Original Sin.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Original Sin",
+				Tags:          EmptyTags.Set(Cover),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "original in a title keeps remix",
+			args: args{
+				filepath: []byte("Remixes (Remix)/The Offspring - Original Prankster.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "The Offspring",
+				Work:          "Original Prankster",
+				Tags:          EmptyTags.Set(Remix),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "studio in an author doesn't cancel",
+			args: args{
+				filepath: []byte("Bootlegs/Studio Killers - Jenny.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Studio Killers",
+				Work:          "Jenny",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "studio in a title doesn't cancel",
+			args: args{
+				filepath: []byte("Live at Wembley/Artist - Studio Song.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Studio Song",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "original mix keeps cover",
+			args: args{
+				filepath: []byte("Covers (Tribute)/Artist - Song (Original Mix).mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				Tags:          EmptyTags.Set(Cover),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
 			},
 		},
 	}
@@ -227,6 +801,55 @@ func TestExtractInfo(t *testing.T) {
 	}
 }
 
+func TestExtractLibraryInfo(t *testing.T) {
+	type args struct {
+		root     []byte
+		filepath []byte
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantInfo Info
+	}{
+		{
+			name: "root left out",
+			args: args{
+				root:     []byte("/mnt/Bootlegs Backup/"),
+				filepath: []byte("/mnt/Bootlegs Backup/Artist/Artist - Song.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "outside root",
+			args: args{
+				root:     []byte("/mnt/Music"),
+				filepath: []byte("/mnt/Bootlegs Backup/Artist - Song.mp3"),
+			},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotInfo := ExtractLibraryInfo(tt.args.root, tt.args.filepath); !reflect.DeepEqual(gotInfo, tt.wantInfo) {
+				t.Errorf("ExtractLibraryInfo() = %v, want %v", gotInfo, tt.wantInfo)
+			}
+		})
+	}
+}
+
 func TestExtractTags(t *testing.T) {
 	type args struct {
 		filename []byte
@@ -301,6 +924,51 @@ func TestExtractTags(t *testing.T) {
 			args: args{filename: []byte(strings.ToLower("a (m parody)"))},
 			want: EmptyTags.Set(Cover),
 		},
+		{
+			name: "translit live 1",
+			args: args{filename: []byte(strings.ToLower("a - b (zhivoj kontsert)"))},
+			want: EmptyTags.Set(Live),
+		},
+		{
+			name: "translit live 2",
+			args: args{filename: []byte(strings.ToLower("a - b na stadione"))},
+			want: EmptyTags.Set(Live),
+		},
+		{
+			name: "translit remix",
+			args: args{filename: []byte(strings.ToLower("a - b (remiks)"))},
+			want: EmptyTags.Set(Remix),
+		},
+		{
+			name: "translit backing track",
+			args: args{filename: []byte(strings.ToLower("a - b (minus)"))},
+			want: EmptyTags.Set(BackingTrack),
+		},
+		{
+			name: "translit interview",
+			args: args{filename: []byte(strings.ToLower("a - intervyu"))},
+			want: EmptyTags.Set(Interview),
+		},
+		{
+			name: "translit volapuk",
+			args: args{filename: []byte(strings.ToLower("a - b (4ernovik)"))},
+			want: EmptyTags.Set(Draft),
+		},
+		{
+			name: "translit radio is not remix",
+			args: args{filename: []byte(strings.ToLower("a - b (video)"))},
+			want: EmptyTags.Set(Radio),
+		},
+		{
+			name: "mixed case",
+			args: args{filename: []byte("A - B (Radio Edit) [Live]")},
+			want: EmptyTags.Set(Radio).Set(Live),
+		},
+		{
+			name: "mixed case original mix",
+			args: args{filename: []byte("A - B (Original Mix)")},
+			want: EmptyTags,
+		},
 		{
 			name: "not cover",
 			args: args{filename: []byte(strings.ToLower("parody name"))},
@@ -359,6 +1027,20 @@ func TestExtractDirTags(t *testing.T) {
 			},
 			wantTags: EmptyTags.Set(Cover),
 		},
+		{
+			name: "parody at the end",
+			args: args{
+				dirname: []byte("Artist - Пародия"),
+			},
+			wantTags: EmptyTags.Set(Cover),
+		},
+		{
+			name: "parody on",
+			args: args{
+				dirname: []byte("Artist - пародия на группу"),
+			},
+			wantTags: EmptyTags.Set(Cover),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
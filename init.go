@@ -12,41 +12,77 @@ var (
 	tagsRe               *regexp.Regexp
 	tagsLiveAtRe         *regexp.Regexp
 	tagsFilenameLiveAtRe *regexp.Regexp
+	tagsDemosRe          *regexp.Regexp
 	tagsInterviewWithRe  *regexp.Regexp
 	tagsCoverBy          *regexp.Regexp
 	tagsMixBy            *regexp.Regexp
 	tagsOriginalMixRe    *regexp.Regexp
+	tagsStudioRe         *regexp.Regexp
+	tagsAlbumVersionRe   *regexp.Regexp
+	tagsOriginalRe       *regexp.Regexp
 	parenthesesRe        *regexp.Regexp
+	titleQuotesRe        *regexp.Regexp
+	trackPrefixRe        *regexp.Regexp
+	featuringRe          *regexp.Regexp
+	remixerRe            *regexp.Regexp
+	remixedByRe          *regexp.Regexp
+	dashRunRe            *regexp.Regexp
+	emptyBracketsRe      *regexp.Regexp
+	entityRe             *regexp.Regexp
 
-	infoFilenameRe *regexp.Regexp
+	infoFilenameRe       *regexp.Regexp
+	infoFilenameSpacedRe *regexp.Regexp
+	spacedDashRe         *regexp.Regexp
+	dashSeparatorRe      *regexp.Regexp
+	yearPrefixRe         *regexp.Regexp
+	trackNumberRe        *regexp.Regexp
+	dashRe               *regexp.Regexp
 )
 
+// Brackets and quotes, including the fullwidth and CJK forms.
 const (
+	openBrackets      = "([（［【〔〖〈《"
+	closeBrackets     = ")]）］】〕〗〉》"
+	openTitleQuotes   = "「『"
+	closeTitleQuotes  = "」』"
+	dashes            = "-－"
+	ideographicSpaces = "\u3000"
+)
+
+const (
+	groupTrack  = "Track"
 	groupAuthor = "Author"
 	groupWork   = "Work"
+
+	groupStandalone = "Standalone"
 )
 
 var groups = map[string][]string{
 	Live.String(): {
 		"live", "bootleg",
 		"(живой )?концерт", "кассета", "радиоэфир", "бутлег",
+		"ライブ", "现场", "現場", "라이브",
 	},
 	Remix.String(): {
 		"remix", "mix", "rmx", "alt", "bass", "boost", "disco", "club", "offmix",
 		"(metal|rock|piano|guitar|sax|danc) version",
 		"ремикс", "микс", "радио", "видео", "клуб", "бас",
+		"リミックス", "混音", "리믹스",
 	},
 	Instrumental.String(): {
 		"instrument", "instrumental", "instrumentals", "acoust",
 		"инструмент", "инструментал",
+		"インスト", "オフボーカル", "off vocal", "纯音乐", "純音樂",
 	},
 	Demo.String(): {
 		"demo",
 		"демо",
+		"デモ",
 	},
 	Orchestral.String(): {
 		"orchestra", "orchestral", "orch",
 		"оркестр",
+		"オーケストラ", "管弦乐",
 	},
 	Interview.String(): {
 		"interview",
@@ -59,10 +95,12 @@ var groups = map[string][]string{
 	Remaster.String(): {
 		"remaster",
 		"ремастер",
+		"リマスター", "리마스터",
 	},
 	Capella.String(): {
 		"capella", "acapella",
 		"капелла", "акапелла",
+		"アカペラ",
 	},
 	Radio.String(): {
 		"radio", "video",
@@ -71,14 +109,17 @@ var groups = map[string][]string{
 	BackingTrack.String(): {
 		"backingtrack", "back(ing)? track", "karaok",
 		"минус", "караоке",
+		"カラオケ", "伴奏", "반주",
 	},
 	Fragment.String(): {
 		"fragment", "cut version",
 		"фрагмент",
+		"tv size", "tv ver", "short ver", "テレビサイズ", "ショートバージョン",
 	},
 	Cover.String(): {
 		"cover", "tribute", "parody",
 		"кавер", "ковер", "перепевка", "на русском", "трибьют", "пародия( на)?",
+		"カバー", "翻唱", "커버",
 	},
 	Rehearsal.String(): {
 		"rehearsal",
@@ -87,6 +128,7 @@ var groups = map[string][]string{
 	Bonus.String(): {
 		"bonus",
 		"бонус",
+		"ボーナストラック",
 	},
 	Draft.String(): {
 		"draft",
@@ -96,95 +138,281 @@ var groups = map[string][]string{
 
 func init() {
 	tagsLiveAtRe = rex.New(
-		rex.Group.Composite(
-			rex.Common.Raw("live at"),
-			rex.Common.Raw(" -[^-]*live( (from|at|on|in) )?"),
-			rex.Common.Raw("bootleg|outtake"),
-			rex.Common.Raw("live vol"),
-			rex.Common.Raw("live album"),
-			rex.Common.Raw("rare track(s)?"),
-			rex.Common.Raw(" - (живой )?концерт (в|на|у|из) "),
-			rex.Common.Raw("na stadione|на стадион(е)?"),
-			rex.Common.Raw("концерт(н)?(ные)? запис(и)?"),
-			rex.Common.Raw("на рад(ио)? "),
+		ignoreCase(),
+		translitRawGroup(
+			"live at",
+			" -[^-]*live( (from|at|on|in) )?",
+			"bootleg|outtake",
+			"live vol",
+			"live album",
+			"rare track(s)?",
+			" - (живой )?концерт (в|на|у|из) ",
+			"на стадион(е)?",
+			"концерт(н)?(ные)? запис(и)?",
+			"на рад(ио)? ",
 		).NonCaptured(),
 	).MustCompile()
 
 	tagsFilenameLiveAtRe = rex.New(
-		rex.Group.Composite(
-			rex.Common.Raw(" - live (from|at|on|in) "),
-			rex.Common.Raw("bootleg|outtake"),
-			rex.Common.Raw(" - (живой )?концерт (в|на|у|из) "),
-			rex.Common.Raw("na stadione|на стадион(е)?"),
-			rex.Common.Raw("концерт(н)?(ные)? запис(и)?"),
-			rex.Common.Raw("на рад(ио)? "),
+		ignoreCase(),
+		translitRawGroup(
+			" - live (from|at|on|in) ",
+			"bootleg|outtake",
+			" - (живой )?концерт (в|на|у|из) ",
+			"на стадион(е)?",
+			"концерт(н)?(ные)? запис(и)?",
+			"на рад(ио)? ",
 		).NonCaptured(),
 	).MustCompile()
 
+	// Directories of demos, like "1993 Studio Demos".
+	tagsDemosRe = rex.New(
+		ignoreCase(),
+		rex.Common.Raw(`(?:^|[^\p{L}\p{N}])`),
+		translitRawGroup(
+			"demos",
+			"demo (recordings|tapes|sessions)",
+			"демо(-| )?запис(и|ь)",
+			"демки",
+		).NonCaptured(),
+		rex.Common.Raw(`(?:$|[^\p{L}\p{N}])`),
+	).MustCompile()
+
 	tagsInterviewWithRe = rex.New(
+		ignoreCase(),
 		rex.Group.Composite(
-			rex.Common.Raw("interview"),
-			rex.Common.Raw("intervyu|интерв|интервью"),
+			translitRawGroup("interview", "интервью"),
+			// The stem is too short to be transliterated safely.
+			rex.Common.Raw("интерв"),
 		).NonCaptured(),
 	).MustCompile()
 
 	tagsCoverBy = rex.New(
-		rex.Group.Composite(
-			rex.Common.Raw("cover by"),
-			rex.Common.Raw(" - cover"),
-			rex.Common.Raw("на русском"),
-			rex.Common.Raw("трибьют(ы)?"),
-			rex.Common.Raw(" - парод(ия|ии)? (( на)|\\,)?"),
+		ignoreCase(),
+		translitRawGroup(
+			"cover by",
+			" - cover",
+			"на русском",
+			"трибьют(ы)?",
+			" - парод(ия|ии)? (( на)|\\,)?",
+			// "пародии, посвящённые" and a parody at the end of the name.
+			" - парод(ия|ии)(\\,|$)",
 		).NonCaptured(),
 	).MustCompile()
 
 	tagsMixBy = rex.New(
-		rex.Group.Composite(
-			rex.Common.Raw("mix by"),
+		ignoreCase(),
+		translitRawGroup(
+			"mix by",
 		).NonCaptured(),
 	).MustCompile()
 
 	tagsOriginalMixRe = rex.New(
-		rex.Group.Composite(
-			rex.Common.Raw("origin(al)? (mix|version)"),
+		ignoreCase(),
+		translitRawGroup(
+			"origin(al)? (mix|version)",
 		).NonCaptured(),
 	).MustCompile()
 
-	tagsRe = rex.New(tagGroups(groups)).MustCompile()
+	// The qualifiers that cancel the tags of the farther segments.
+	// A single word like "Studio" or "Original" cancels only in brackets
+	// or as the whole directory name, it may be a part of a title.
+
+	tagsStudioRe = qualifierRe(
+		[]string{
+			"studio( (version|recordings?|demos?|sessions?|outtakes|album))?",
+			"студи(я|и)",
+			"студийн(ая|ые) (верси(я|и)|запис(ь|и)|демо)",
+		},
+		[]string{
+			"studio (version|recordings?|demos?|sessions?|outtakes|album)",
+			"студийн(ая|ые) (верси(я|и)|запис(ь|и)|демо)",
+		},
+	)
+
+	tagsAlbumVersionRe = qualifierRe(
+		[]string{
+			"album (version|edit)",
+			"lp version",
+			"альбомн(ая|ые) верси(я|и)",
+		},
+		[]string{
+			"album (version|edit)",
+			"lp version",
+			"альбомн(ая|ые) верси(я|и)",
+		},
+	)
+
+	tagsOriginalRe = qualifierRe(
+		[]string{
+			"original",
+			"оригинал",
+		},
+		nil,
+	)
+
+	tagsRe = rex.New(ignoreCase(), tagGroups(groups)).MustCompile()
 
 	parenthesesRe = rex.New(
-		rex.Common.Class(
-			rex.Chars.Single('('),
-			rex.Chars.Single('['),
-		),
+		rex.Chars.Runes(openBrackets),
 
 		rex.Common.NotClass(
-			rex.Chars.Single('('),
-			rex.Chars.Single(')'),
-			rex.Chars.Single('['),
-			rex.Chars.Single(']'),
+			rex.Chars.Runes(openBrackets),
+			rex.Chars.Runes(closeBrackets),
 		).Repeat().OneOrMore(),
 
 		rex.Group.Composite(
-			rex.Common.Class(
-				rex.Chars.Single(')'),
-				rex.Chars.Single(']'),
-			),
+			rex.Chars.Runes(closeBrackets),
 			rex.Chars.End(),
 		),
 	).MustCompile()
 
-	infoFilenameRe = rex.New(
+	titleQuotesRe = rex.New(
+		rex.Chars.Runes(openTitleQuotes),
+		rex.Common.NotClass(
+			rex.Chars.Runes(openTitleQuotes),
+			rex.Chars.Runes(closeTitleQuotes),
+		).Repeat().OneOrMore(),
+		rex.Chars.Runes(closeTitleQuotes).Repeat().ZeroOrOne(),
+	).MustCompile()
+
+	trackPrefixRe = rex.New(
+		rex.Chars.Begin(),
+		rex.Chars.Digits().Repeat().OneOrMore(),
+		rex.Chars.Single('.').Repeat().ZeroOrOne(),
+		whitespace().Repeat().ZeroOrMore(),
+	).MustCompile()
+
+	featuringRe = rex.New(
+		ignoreCase(),
+		rex.Group.Composite(
+			rex.Chars.Begin(),
+			whitespace(),
+			rex.Chars.Runes(openBrackets),
+		).NonCaptured(),
+		tagRawGroup(
+			"feat\\.?",
+			"ft\\.",
+			"featuring",
+			"при уч\\.",
+			"при участии",
+			"с участием",
+		).NonCaptured(),
+		whitespace().Repeat().OneOrMore(),
+	).MustCompile()
+
+	remixerRe = rex.New(
+		ignoreCase(),
+		rex.Chars.Begin(),
+		rex.Group.Define(
+			rex.Chars.Any().Repeat().OneOrMore(),
+		),
+		whitespace().Repeat().OneOrMore(),
+		translitRawGroup("remix", "rmx", "mix", "edit", "ремикс", "микс").NonCaptured(),
+		rex.Chars.End(),
+	).MustCompile()
+
+	remixedByRe = rex.New(
+		ignoreCase(),
+		translitRawGroup("(?:re)?mix(?:ed)? by", "ремикс от").NonCaptured(),
+		whitespace().Repeat().OneOrMore(),
+		rex.Group.Define(
+			rex.Chars.Any().Repeat().OneOrMore(),
+		),
+	).MustCompile()
+
+	// Separators left by the missing fields, like "01 -  - Song".
+	dashRunRe = rex.New(
+		whitespace().Repeat().ZeroOrMore(),
+		rex.Chars.Runes(dashes),
 		rex.Group.NonCaptured(
-			rex.Chars.Digits().Repeat().OneOrMore(),
-			rex.Chars.Single('.').Repeat().ZeroOrOne(),
+			whitespace().Repeat().OneOrMore(),
+			rex.Chars.Runes(dashes),
+		).Repeat().OneOrMore(),
+		whitespace().Repeat().ZeroOrMore(),
+	).MustCompile()
 
-			rex.Group.NonCaptured(
-				rex.Chars.Whitespace().Repeat().ZeroOrOne(),
-				rex.Chars.Single('-'),
-			).Repeat().ZeroOrOne(),
+	emptyBracketsRe = rex.New(
+		whitespace().Repeat().ZeroOrMore(),
+		rex.Chars.Runes(openBrackets),
+		whitespace().Repeat().ZeroOrMore(),
+		rex.Chars.Runes(closeBrackets),
+	).MustCompile()
 
-			rex.Chars.Whitespace().Repeat().ZeroOrOne(),
+	// The hash of numeric HTML entities like "&#35;".
+	entityRe = rex.New(
+		rex.Chars.Single('&'),
+		rex.Group.Composite(
+			rex.Common.Raw("#[xX][0-9a-fA-F]+"),
+			rex.Common.Raw("#[0-9]+"),
+			rex.Common.Raw("[a-zA-Z][a-zA-Z0-9]*"),
+		).NonCaptured(),
+		rex.Chars.Single(';'),
+	).MustCompile()
+
+	infoFilenameRe = infoFilenameRegexp(
+		whitespace().Repeat().ZeroOrOne(),
+		rex.Chars.Runes(dashes),
+		whitespace().Repeat().ZeroOrOne(),
+	)
+
+	// Spaced dashes separate the author and the work when there are any,
+	// so the dashes of "Jay-Z" and "Night-Time" are kept.
+	infoFilenameSpacedRe = infoFilenameRegexp(
+		whitespace().Repeat().OneOrMore(),
+		rex.Chars.Runes(dashes),
+		whitespace().Repeat().OneOrMore(),
+	)
+
+	spacedDashRe = rex.New(
+		whitespace().Repeat().OneOrMore(),
+		rex.Chars.Runes(dashes),
+		whitespace().Repeat().OneOrMore(),
+	).MustCompile()
+
+	dashSeparatorRe = rex.New(
+		rex.Chars.Begin(),
+		whitespace().Repeat().ZeroOrMore(),
+		rex.Chars.Runes(dashes),
+		whitespace().Repeat().ZeroOrMore(),
+	).MustCompile()
+
+	dashRe = rex.New(
+		whitespace().Repeat().ZeroOrMore(),
+		rex.Chars.Runes(dashes),
+		whitespace().Repeat().ZeroOrMore(),
+	).MustCompile()
+
+	trackNumberRe = rex.New(
+		rex.Chars.Begin(),
+		rex.Group.Define(
+			rex.Chars.Digits().Repeat().Between(1, 3),
+		),
+		trackSeparator(),
+	).MustCompile()
+
+	yearPrefixRe = rex.New(
+		rex.Chars.Begin(),
+		rex.Group.Define(
+			rex.Group.Composite(rex.Common.Raw("19"), rex.Common.Raw("20")).NonCaptured(),
+			rex.Chars.Digits().Repeat().Exactly(2),
+		),
+		whitespace().Repeat().ZeroOrMore(),
+		rex.Chars.Runes(dashes),
+		whitespace().Repeat().ZeroOrMore(),
+	).MustCompile()
+}
+
+// infoFilenameRegexp matches the track number, the author and the work
+// of a filename with the separator between the author and the work.
+func infoFilenameRegexp(separator ...dialect.Token) *regexp.Regexp {
+	return rex.New(
+		rex.Group.NonCaptured(
+			rex.Group.Define(
+				rex.Chars.Digits().Repeat().OneOrMore(),
+			).WithName(groupTrack),
+
+			trackSeparator(),
 		).Repeat().ZeroOrOne(),
 
 		rex.Group.Composite(
@@ -193,9 +421,7 @@ func init() {
 					rex.Chars.Any().Repeat().OneOrMore(),
 				).WithName(groupAuthor),
 
-				rex.Chars.Whitespace().Repeat().ZeroOrOne(),
-				rex.Chars.Single('-'),
-				rex.Chars.Whitespace().Repeat().ZeroOrOne(),
+				rex.Group.NonCaptured(separator...),
 
 				rex.Group.Define(
 					rex.Chars.Any().Repeat().OneOrMore(),
@@ -207,13 +433,42 @@ func init() {
 			).WithName(groupWork),
 		).NonCaptured(),
 	).MustCompile()
+}
 
+// trackSeparator matches the separator after a track number, like ". "
+// or " - ". The number must be separated, like "10cc" is not a track.
+func trackSeparator() base.GroupToken {
+	return rex.Group.Composite(
+		rex.Group.NonCaptured(
+			rex.Chars.Single('.'),
+			whitespace().Repeat().ZeroOrMore(),
+			rex.Group.NonCaptured(
+				rex.Chars.Runes(dashes),
+				whitespace().Repeat().ZeroOrMore(),
+			).Repeat().ZeroOrOne(),
+		),
+		rex.Group.NonCaptured(
+			whitespace().Repeat().ZeroOrMore(),
+			rex.Chars.Runes(dashes),
+			whitespace().Repeat().ZeroOrMore(),
+		),
+		whitespace().Repeat().OneOrMore(),
+	).NonCaptured()
 }
 
 func tagGroups(groups map[string][]string) base.GroupToken {
 	var tkns []dialect.Token
 
 	for groupName, tokens := range groups {
+		var others []string
+		for otherName, otherTokens := range groups {
+			if otherName != groupName {
+				others = append(others, otherTokens...)
+			}
+		}
+
+		tokens = withTranslit(tokens, others)
+
 		grp := tagRawGroup(tokens...).WithName(groupName)
 		tkns = append(tkns, grp)
 	}
@@ -221,12 +476,61 @@ func tagGroups(groups map[string][]string) base.GroupToken {
 	return rex.Group.Composite(tkns...).NonCaptured()
 }
 
+func translitRawGroup(raws ...string) base.GroupToken {
+	return tagRawGroup(withTranslit(raws, nil)...)
+}
+
 func tagRawGroup(raws ...string) base.GroupToken {
 	var tkns []dialect.Token
 
 	for _, r := range raws {
-		tkns = append(tkns, rex.Common.Raw(r))
+		tkns = append(tkns, rex.Common.Raw(foldString(r)))
 	}
 
 	return rex.Group.Composite(tkns...)
 }
+
+// qualifierRe matches the qualifier in brackets, like "(Studio)", or as
+// the whole name, like "Studio", in the group groupStandalone. The phrases
+// are matched anywhere between the words, like "Studio Demos" in
+// "1993 Studio Demos".
+func qualifierRe(qualifiers, phrases []string) *regexp.Regexp {
+	alternatives := []dialect.Token{
+		rex.Group.Define(
+			rex.Chars.Runes(openBrackets),
+			whitespace().Repeat().ZeroOrMore(),
+			translitRawGroup(qualifiers...).NonCaptured(),
+			whitespace().Repeat().ZeroOrMore(),
+			rex.Chars.Runes(closeBrackets),
+		).NonCaptured(),
+		rex.Group.Define(
+			rex.Chars.Begin(),
+			whitespace().Repeat().ZeroOrMore(),
+			translitRawGroup(qualifiers...).NonCaptured(),
+			whitespace().Repeat().ZeroOrMore(),
+			rex.Chars.End(),
+		).WithName(groupStandalone),
+	}
+
+	if len(phrases) > 0 {
+		alternatives = append(alternatives, rex.Group.Define(
+			rex.Common.Raw(`(?:^|[^\p{L}\p{N}])`),
+			translitRawGroup(phrases...).NonCaptured(),
+			rex.Common.Raw(`(?:$|[^\p{L}\p{N}])`),
+		).NonCaptured())
+	}
+
+	return rex.New(ignoreCase(), rex.Group.Composite(alternatives...).NonCaptured()).MustCompile()
+}
+
+// ignoreCase makes the expression match letters in any case, Cyrillic included.
+func ignoreCase() base.RawToken {
+	return rex.Common.Raw("(?i)")
+}
+
+func whitespace() base.ClassToken {
+	return rex.Common.Class(
+		rex.Chars.Whitespace(),
+		rex.Chars.Runes(ideographicSpaces),
+	)
+}
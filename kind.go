@@ -0,0 +1,68 @@
+package musicfile
+
+import (
+	"fmt"
+)
+
+// Kind is a kind of a file found in music folders.
+type Kind int
+
+const (
+	// KindUnknown is a file without a known extension.
+	KindUnknown Kind = iota
+	KindAudio
+	KindImage
+	KindCuesheet
+	KindPlaylist
+	KindRipLog
+	KindLyrics
+	KindOther
+)
+
+var kindNames = []string{
+	"unknown",
+	"audio",
+	"image",
+	"cuesheet",
+	"playlist",
+	"riplog",
+	"lyrics",
+	"other",
+}
+
+// Classify tells the kind of the file by its path or name.
+func Classify(filepath []byte) Kind {
+	path := SplitPath(filepath, PathAuto)
+	name := normalize(path[len(path)-1])
+
+	_, ext := splitExtension(name.b)
+
+	return ext.kind
+}
+
+// describesTrack reports whether the name of a file of the kind
+// describes a track, so its author, work and tags are extracted.
+func (k Kind) describesTrack() bool {
+	return k == KindUnknown || k == KindAudio || k == KindLyrics
+}
+
+func (k Kind) String() string {
+	if k >= 0 && int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "unknown"
+}
+
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *Kind) UnmarshalText(text []byte) error {
+	for i, name := range kindNames {
+		if name == string(text) {
+			*k = Kind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown file kind '%s'", text)
+}
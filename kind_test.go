@@ -0,0 +1,76 @@
+package musicfile
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		filepath string
+		want     Kind
+	}{
+		{filepath: "Artist/Album/01 - Song.mp3", want: KindAudio},
+		{filepath: "Artist/Album/01 - Song.FLAC.part", want: KindAudio},
+		{filepath: "Artist/Album/01 - Song.part", want: KindUnknown},
+		{filepath: "Artist/Album/cover.jpg", want: KindImage},
+		{filepath: `Artist\Album\folder.PNG`, want: KindImage},
+		{filepath: "Artist/Album/Artist - Album.cue", want: KindCuesheet},
+		{filepath: "Artist/Album/Artist - Album.log", want: KindRipLog},
+		{filepath: "Artist/Album/Artist - Album.m3u8", want: KindPlaylist},
+		{filepath: "Artist/Album/01 - Song.lrc", want: KindLyrics},
+		{filepath: "Artist/Album/info.nfo", want: KindOther},
+		{filepath: "Artist/Album/readme.txt", want: KindOther},
+		{filepath: "Artist/Album/Thumbs.db", want: KindOther},
+		{filepath: "Artist/Album/Mr. Big", want: KindUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filepath, func(t *testing.T) {
+			if got := Classify([]byte(tt.filepath)); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRegisterExtension(t *testing.T) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		RegisterExtension(".testaudio", FamilyMPEG)
+	}()
+
+	for i := 0; i < 100; i++ {
+		Classify([]byte("Artist - Song.mp3"))
+	}
+
+	<-done
+
+	if got := Classify([]byte("Artist - Song.TestAudio")); got != KindAudio {
+		t.Errorf("Classify() = %v, want %v", got, KindAudio)
+	}
+
+	RegisterExtension(" TestAudio2", FamilyMPEG)
+
+	if got := Classify([]byte("Artist - Song.testaudio2")); got != KindAudio {
+		t.Errorf("Classify() = %v, want %v", got, KindAudio)
+	}
+}
+
+func TestExtractInfoKind(t *testing.T) {
+	info := ExtractInfo([]byte("Live at Wembley/cover.jpg"))
+	want := Info{FileExtension: ".jpg", Kind: KindImage}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("ExtractInfo() = %v, want %v", info, want)
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"file_extension":".jpg","kind":"image"}` {
+		t.Errorf("json.Marshal() = %s", b)
+	}
+}
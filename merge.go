@@ -0,0 +1,281 @@
+package musicfile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Source is where a field of the info came from.
+type Source int
+
+const (
+	// SourceNone marks a field that no source has.
+	SourceNone Source = iota
+	SourceFilename
+	SourceDirectory
+	SourceEmbedded
+)
+
+var sourceNames = []string{
+	"none",
+	"filename",
+	"directory",
+	"embedded",
+}
+
+func (s Source) String() string {
+	if s >= 0 && int(s) < len(sourceNames) {
+		return sourceNames[s]
+	}
+	return "none"
+}
+
+func (s Source) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Source) UnmarshalText(text []byte) error {
+	for i, name := range sourceNames {
+		if name == string(text) {
+			*s = Source(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown source '%s'", text)
+}
+
+// Field is a field of the info that is merged from the sources.
+type Field int
+
+const (
+	FieldAuthor Field = iota
+	FieldAlbum
+	FieldWork
+	FieldTrack
+	FieldDisc
+	FieldYear
+	FieldTags
+)
+
+var fieldNames = []string{
+	"author",
+	"album",
+	"work",
+	"track",
+	"disc",
+	"year",
+	"tags",
+}
+
+func (f Field) String() string {
+	if f >= 0 && int(f) < len(fieldNames) {
+		return fieldNames[f]
+	}
+	return "unknown"
+}
+
+func (f Field) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+func (f *Field) UnmarshalText(text []byte) error {
+	for i, name := range fieldNames {
+		if name == string(text) {
+			*f = Field(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown field '%s'", text)
+}
+
+// Precedence lists the sources of each field from the most trusted one.
+// A field takes the value of the first source that has it, the sources
+// that aren't listed are ignored. Fields missing from the precedence
+// follow DefaultPrecedence.
+type Precedence map[Field][]Source
+
+// DefaultPrecedence trusts the embedded metadata over the filename
+// and the filename over the directories.
+var DefaultPrecedence = Precedence{
+	FieldAuthor: {SourceEmbedded, SourceFilename, SourceDirectory},
+	FieldAlbum:  {SourceEmbedded, SourceFilename, SourceDirectory},
+	FieldWork:   {SourceEmbedded, SourceFilename, SourceDirectory},
+	FieldTrack:  {SourceEmbedded, SourceFilename, SourceDirectory},
+	FieldDisc:   {SourceEmbedded, SourceFilename, SourceDirectory},
+	FieldYear:   {SourceEmbedded, SourceFilename, SourceDirectory},
+	FieldTags:   {SourceEmbedded, SourceFilename, SourceDirectory},
+}
+
+func (p Precedence) sources(field Field) []Source {
+	if sources, ok := p[field]; ok {
+		return sources
+	}
+	return DefaultPrecedence[field]
+}
+
+// MergedInfo is the info merged from several sources.
+type MergedInfo struct {
+	Info
+	// Sources tells where each non-empty field came from. The tags
+	// are the union of the tags of all the sources, so they have
+	// no single source and aren't listed.
+	Sources map[Field]Source `json:"sources,omitempty"`
+	// Conflicts lists the values of the sources that disagree
+	// with the chosen ones.
+	Conflicts []Conflict `json:"conflicts,omitempty"`
+}
+
+// Conflict is a value of a source that differs from the chosen value.
+type Conflict struct {
+	Field       Field  `json:"field"`
+	Source      Source `json:"source"`
+	Value       string `json:"value"`
+	OtherSource Source `json:"other_source"`
+	OtherValue  string `json:"other_value"`
+}
+
+// MergeInfo merges the info of the sources by the precedence.
+// The file extension, family and kind come from the filename.
+// Text values that differ only in case and spacing don't conflict.
+// The tags are the union of the tags of all the sources, so they
+// never conflict.
+func MergeInfo(sources map[Source]Info, precedence Precedence) MergedInfo {
+	file := sources[SourceFilename]
+
+	merged := MergedInfo{
+		Info: Info{
+			FileExtension: file.FileExtension,
+			Family:        file.Family,
+			Kind:          file.Kind,
+			Repaired:      file.Repaired || sources[SourceDirectory].Repaired,
+		},
+		Sources: map[Field]Source{},
+	}
+
+	for field := FieldAuthor; field <= FieldTags; field++ {
+		var chosen Source
+
+		for _, source := range precedence.sources(field) {
+			info, ok := sources[source]
+			if !ok {
+				continue
+			}
+
+			value := fieldValue(info, field)
+			if value == "" {
+				continue
+			}
+
+			if field == FieldTags {
+				merged.Tags = merged.Tags.Append(info.Tags)
+				continue
+			}
+
+			if chosen == SourceNone {
+				chosen = source
+				merged.Info = setField(merged.Info, info, field)
+				continue
+			}
+
+			if current := fieldValue(merged.Info, field); !sameValue(current, value) {
+				merged.Conflicts = append(merged.Conflicts, Conflict{
+					Field:       field,
+					Source:      chosen,
+					Value:       current,
+					OtherSource: source,
+					OtherValue:  value,
+				})
+			}
+		}
+
+		if chosen != SourceNone {
+			merged.Sources[field] = chosen
+		}
+	}
+
+	return merged
+}
+
+// MergeFileInfo extracts music info from the file path and the embedded
+// metadata of the file and merges them by the precedence. A nil
+// precedence is DefaultPrecedence. The info of the path alone is
+// returned with the error when the metadata can't be read.
+func MergeFileInfo(filepath []byte, r io.ReadSeeker, precedence Precedence) (MergedInfo, error) {
+	file, dir := extractPathSources(SplitPath(filepath, PathAuto))
+
+	sources := map[Source]Info{
+		SourceFilename:  file,
+		SourceDirectory: dir,
+	}
+
+	embedded, err := ReadMetadata(r)
+	switch {
+	case err == nil:
+		sources[SourceEmbedded] = embedded
+	case !errors.Is(err, ErrNoMetadata):
+		return MergeInfo(sources, precedence), err
+	}
+
+	return MergeInfo(sources, precedence), nil
+}
+
+// fieldValue returns the field as text, empty when it is not set.
+func fieldValue(info Info, field Field) string {
+	var n int
+
+	switch field {
+	case FieldAuthor:
+		return info.Author
+	case FieldAlbum:
+		return info.Album
+	case FieldWork:
+		return info.Work
+	case FieldTags:
+		names := make([]string, bits)
+		n = info.Tags.Names(names)
+		return strings.Join(names[:n], ",")
+	case FieldTrack:
+		n = info.Track
+	case FieldDisc:
+		n = info.Disc
+	case FieldYear:
+		n = info.Year
+	}
+
+	if n == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(n)
+}
+
+// setField sets the field of dst to the one of src.
+func setField(dst, src Info, field Field) Info {
+	switch field {
+	case FieldAuthor:
+		dst.Author = src.Author
+	case FieldAlbum:
+		dst.Album = src.Album
+	case FieldWork:
+		dst.Work = src.Work
+	case FieldTrack:
+		dst.Track = src.Track
+	case FieldDisc:
+		dst.Disc = src.Disc
+	case FieldYear:
+		dst.Year = src.Year
+	case FieldTags:
+		dst.Tags = src.Tags
+	}
+	return dst
+}
+
+func sameValue(a, b string) bool {
+	return strings.EqualFold(
+		strings.Join(strings.Fields(foldString(a)), " "),
+		strings.Join(strings.Fields(foldString(b)), " "),
+	)
+}
@@ -0,0 +1,193 @@
+package musicfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMergeInfo(t *testing.T) {
+	sources := map[Source]Info{
+		SourceFilename: {
+			Author:        "Artist",
+			Work:          "Song",
+			Track:         2,
+			Tags:          EmptyTags.Set(Live),
+			FileExtension: ".mp3",
+			Family:        FamilyMPEG,
+			Kind:          KindAudio,
+		},
+		SourceDirectory: {
+			Tags: EmptyTags.Set(Bonus),
+		},
+		SourceEmbedded: {
+			Author: "The Artist",
+			Album:  "Album",
+			Work:   "song",
+			Track:  3,
+			Year:   2011,
+		},
+	}
+
+	type args struct {
+		sources    map[Source]Info
+		precedence Precedence
+	}
+	tests := []struct {
+		name string
+		args args
+		want MergedInfo
+	}{
+		{
+			name: "default precedence",
+			args: args{sources: sources},
+			want: MergedInfo{
+				Info: Info{
+					Author:        "The Artist",
+					Album:         "Album",
+					Work:          "song",
+					Track:         3,
+					Year:          2011,
+					Tags:          EmptyTags.Set(Live).Set(Bonus),
+					FileExtension: ".mp3",
+					Family:        FamilyMPEG,
+					Kind:          KindAudio,
+				},
+				Sources: map[Field]Source{
+					FieldAuthor: SourceEmbedded,
+					FieldAlbum:  SourceEmbedded,
+					FieldWork:   SourceEmbedded,
+					FieldTrack:  SourceEmbedded,
+					FieldYear:   SourceEmbedded,
+				},
+				Conflicts: []Conflict{
+					{Field: FieldAuthor, Source: SourceEmbedded, Value: "The Artist", OtherSource: SourceFilename, OtherValue: "Artist"},
+					{Field: FieldTrack, Source: SourceEmbedded, Value: "3", OtherSource: SourceFilename, OtherValue: "2"},
+				},
+			},
+		},
+		{
+			name: "track from filename",
+			args: args{
+				sources: sources,
+				precedence: Precedence{
+					FieldTrack: {SourceFilename, SourceEmbedded},
+					FieldTags:  {SourceFilename},
+				},
+			},
+			want: MergedInfo{
+				Info: Info{
+					Author:        "The Artist",
+					Album:         "Album",
+					Work:          "song",
+					Track:         2,
+					Year:          2011,
+					Tags:          EmptyTags.Set(Live),
+					FileExtension: ".mp3",
+					Family:        FamilyMPEG,
+					Kind:          KindAudio,
+				},
+				Sources: map[Field]Source{
+					FieldAuthor: SourceEmbedded,
+					FieldAlbum:  SourceEmbedded,
+					FieldWork:   SourceEmbedded,
+					FieldTrack:  SourceFilename,
+					FieldYear:   SourceEmbedded,
+				},
+				Conflicts: []Conflict{
+					{Field: FieldAuthor, Source: SourceEmbedded, Value: "The Artist", OtherSource: SourceFilename, OtherValue: "Artist"},
+					{Field: FieldTrack, Source: SourceFilename, Value: "2", OtherSource: SourceEmbedded, OtherValue: "3"},
+				},
+			},
+		},
+		{
+			name: "no sources",
+			args: args{sources: map[Source]Info{}},
+			want: MergedInfo{
+				Sources: map[Field]Source{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeInfo(tt.args.sources, tt.args.precedence); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeFileInfo(t *testing.T) {
+	file := testID3v2Tag(4, 0,
+		testID3Frame(4, "TPE1", utf8Text("artist")),
+		testID3Frame(4, "TIT2", utf8Text("Real Title")),
+	)
+
+	got, err := MergeFileInfo([]byte("Live at Wembley/Artist - Title.mp3"), bytes.NewReader(file), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"author":"artist","work":"Real Title","tags":1,"file_extension":".mp3","family":"mpeg","kind":"audio",` +
+		`"sources":{"author":"embedded","work":"embedded"},` +
+		`"conflicts":[{"field":"work","source":"embedded","value":"Real Title","other_source":"filename","other_value":"Title"}]}`
+	if string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+}
+
+func TestMergeFileInfo_malformed(t *testing.T) {
+	file := []byte("ID3\x04\x00\x00\x7f\x7f\x7f\x7f")
+
+	got, err := MergeFileInfo([]byte("Bootlegs/Artist - Song.mp3"), bytes.NewReader(file), nil)
+	if !errors.Is(err, ErrMalformedMetadata) {
+		t.Fatalf("MergeFileInfo() error = %v, wantErr %v", err, ErrMalformedMetadata)
+	}
+
+	want := MergedInfo{
+		Info: Info{
+			Author:        "Artist",
+			Work:          "Song",
+			Tags:          EmptyTags.Set(Live),
+			FileExtension: ".mp3",
+			Family:        FamilyMPEG,
+			Kind:          KindAudio,
+		},
+		Sources: map[Field]Source{
+			FieldAuthor: SourceFilename,
+			FieldWork:   SourceFilename,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MergeFileInfo() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMergeFileInfo_qualifiers(t *testing.T) {
+	file := testID3v2Tag(4, 0,
+		testID3Frame(4, "TPE1", utf8Text("Artist feat. Guest")),
+		testID3Frame(4, "TIT2", utf8Text("Title (Live) [Remastered]")),
+	)
+
+	got, err := MergeFileInfo([]byte("Artist - Title (Live).mp3"), bytes.NewReader(file), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Conflicts != nil {
+		t.Errorf("MergeFileInfo().Conflicts = %+v, want none", got.Conflicts)
+	}
+	if want := EmptyTags.Set(Live).Set(Remaster); got.Tags != want {
+		t.Errorf("MergeFileInfo().Tags = %v, want %v", got.Tags, want)
+	}
+	if got.Author != "Artist" || got.Work != "Title" {
+		t.Errorf("MergeFileInfo() = %q - %q, want %q - %q", got.Author, got.Work, "Artist", "Title")
+	}
+}
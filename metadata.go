@@ -0,0 +1,186 @@
+package musicfile
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// metadata is the embedded metadata of an audio file.
+type metadata struct {
+	artist  string
+	title   string
+	album   string
+	version string
+	track   int
+	disc    int
+	year    int
+}
+
+// ReadMetadata reads the embedded metadata of an MP3, FLAC, Ogg or MP4 file.
+// The format is detected by the content of the file.
+func ReadMetadata(r io.ReadSeeker) (Info, error) {
+	read, err := detectFormat(r)
+	if err != nil {
+		return Info{}, err
+	}
+
+	m, err := read(r)
+	if err != nil {
+		return Info{}, err
+	}
+
+	return m.info(), nil
+}
+
+// ExtractFileInfo extracts music info from the file path and the embedded
+// metadata of the file. Non-empty embedded fields take precedence,
+// use MergeFileInfo to choose the precedence and to review the conflicts.
+// The info of the path is returned with the error when the metadata
+// can't be read.
+func ExtractFileInfo(filepath []byte, r io.ReadSeeker) (Info, error) {
+	merged, err := MergeFileInfo(filepath, r, nil)
+	return merged.Info, err
+}
+
+// detectFormat returns the metadata reader for the format of the file.
+func detectFormat(r io.ReadSeeker) (func(io.ReadSeeker) (metadata, error), error) {
+	start, err := skipID3v2(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, offset := range []int64{0, start} {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		var magic [4]byte
+
+		if _, err := io.ReadFull(r, magic[:]); err != nil {
+			break
+		}
+
+		switch string(magic[:]) {
+		case "fLaC":
+			return readFLAC, nil
+		case "OggS":
+			return readOgg, nil
+		}
+	}
+
+	if ok, err := isMP4(r); ok || err != nil {
+		return readMP4, err
+	}
+
+	return readID3, nil
+}
+
+// metadataFields are the embedded text fields by their names
+// like "artist" and "title".
+type metadataFields map[string]string
+
+func (f metadataFields) metadata() metadata {
+	m := metadata{
+		artist:  f["artist"],
+		title:   f["title"],
+		album:   f["album"],
+		version: f["version"],
+		track:   parseNumber(f["track"]),
+		disc:    parseNumber(f["disc"]),
+		year:    parseNumber(f["year"]),
+	}
+
+	if m.artist == "" {
+		m.artist = f["albumartist"]
+	}
+	if m.year == 0 {
+		m.year = parseNumber(f["originalyear"])
+	}
+
+	return m
+}
+
+// fill fills the empty fields with the fields of other.
+func (m metadata) fill(other metadata) metadata {
+	if m.artist == "" {
+		m.artist = other.artist
+	}
+	if m.title == "" {
+		m.title = other.title
+	}
+	if m.album == "" {
+		m.album = other.album
+	}
+	if m.version == "" {
+		m.version = other.version
+	}
+	if m.track == 0 {
+		m.track = other.track
+	}
+	if m.disc == 0 {
+		m.disc = other.disc
+	}
+	if m.year == 0 {
+		m.year = other.year
+	}
+	return m
+}
+
+// info cleans the title and the artist like ExtractTitleInfo, so the
+// qualifiers and the featured artists don't end up in the work and the author.
+func (m metadata) info() Info {
+	info := ExtractTitleInfo(m.title, m.artist, m.album).Info
+
+	info.Track = m.track
+	info.Disc = m.disc
+	info.Year = m.year
+	info.Tags = info.Tags.Append(versionTags(m.version))
+
+	return info
+}
+
+// versionTags extracts the tags from a version or subtitle like "Live at Wembley".
+func versionTags(version string) Tags {
+	if strings.TrimSpace(version) == "" {
+		return EmptyTags
+	}
+	// Filenames keep the version in parentheses.
+	return ExtractFilenameTags([]byte("(" + version + ")"))
+}
+
+// mergeInfo merges the info extracted from the path with the embedded one.
+// Non-empty embedded fields take precedence, the tags are combined.
+func mergeInfo(path, embedded Info) Info {
+	sources := map[Source]Info{
+		SourceFilename: path,
+		SourceEmbedded: embedded,
+	}
+	return MergeInfo(sources, nil).Info
+}
+
+// parseNumber parses the leading number of values like "3/12" and "2011-05-01".
+func parseNumber(s string) int {
+	s = strings.TrimSpace(s)
+
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// noMetadata turns the errors of reading a too short file into ErrNoMetadata.
+func noMetadata(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrNoMetadata
+	}
+	return err
+}
@@ -0,0 +1,277 @@
+package musicfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	mp4AtomHeaderSize = 8
+	// mp4MaxItemSize limits the metadata items that are read into memory.
+	mp4MaxItemSize = 1 << 20
+)
+
+// mp4Items maps the iTunes metadata items to the fields of the metadata.
+var mp4Items = map[string]string{
+	"\xa9ART": "artist",
+	"aART":    "albumartist",
+	"\xa9nam": "title",
+	"\xa9alb": "album",
+	"\xa9day": "year",
+	"trkn":    "track",
+	"disk":    "disc",
+}
+
+// errAtomNotFound is returned when the file has no atom on the path.
+var errAtomNotFound = errors.New("atom not found")
+
+// ReadMP4 reads the iTunes metadata from the moov/udta/meta/ilst atoms
+// of an MP4 or M4A file.
+func ReadMP4(r io.ReadSeeker) (Info, error) {
+	m, err := readMP4(r)
+	if err != nil {
+		return Info{}, err
+	}
+	return m.info(), nil
+}
+
+func readMP4(r io.ReadSeeker) (metadata, error) {
+	end, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return metadata{}, err
+	}
+
+	if ok, err := isMP4(r); !ok {
+		if err == nil {
+			err = ErrNoMetadata
+		}
+		return metadata{}, err
+	}
+
+	moov, err := findAtom(r, 0, end, "moov")
+	if err != nil {
+		return metadata{}, noAtom(err)
+	}
+
+	// The meta atom is usually in moov/udta, but some writers put it in moov.
+	meta, err := findAtom(r, moov.start, moov.end, "udta", "meta")
+	if errors.Is(err, errAtomNotFound) {
+		meta, err = findAtom(r, moov.start, moov.end, "meta")
+	}
+	if err != nil {
+		return metadata{}, noAtom(err)
+	}
+
+	if meta, err = skipFullAtomHeader(r, meta); err != nil {
+		return metadata{}, err
+	}
+
+	ilst, err := findAtom(r, meta.start, meta.end, "ilst")
+	if err != nil {
+		return metadata{}, noAtom(err)
+	}
+
+	fields := metadataFields{}
+
+	err = walkAtoms(r, ilst.start, ilst.end, func(item atom) error {
+		field, ok := mp4Items[item.typ]
+		if !ok {
+			return nil
+		}
+
+		data, err := findAtom(r, item.start, item.end, "data")
+		if err != nil {
+			return nil
+		}
+
+		value, err := readMP4Data(r, data, field)
+		if err != nil {
+			return err
+		}
+
+		if _, dup := fields[field]; !dup && value != "" {
+			fields[field] = value
+		}
+
+		return nil
+	})
+	if err != nil {
+		return metadata{}, err
+	}
+
+	return fields.metadata(), nil
+}
+
+// isMP4 reports whether the file starts with the ftyp atom.
+func isMP4(r io.ReadSeeker) (bool, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	var header [mp4AtomHeaderSize]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return false, noMetadata(err)
+	}
+
+	return string(header[4:8]) == "ftyp", nil
+}
+
+// atom is the data of an MP4 atom without its header.
+type atom struct {
+	typ        string
+	start, end int64
+}
+
+// findAtom finds the atom by the path of types within the data.
+func findAtom(r io.ReadSeeker, start, end int64, path ...string) (atom, error) {
+	found := atom{start: start, end: end}
+
+	for _, typ := range path {
+		parent := found
+		found = atom{}
+
+		err := walkAtoms(r, parent.start, parent.end, func(a atom) error {
+			if a.typ == typ {
+				found = a
+				return io.EOF
+			}
+			return nil
+		})
+
+		switch {
+		case errors.Is(err, io.EOF):
+		case err != nil:
+			return atom{}, err
+		default:
+			return atom{}, errAtomNotFound
+		}
+	}
+
+	return found, nil
+}
+
+// walkAtoms calls fn for the atoms within the data
+// until fn returns an error.
+func walkAtoms(r io.ReadSeeker, start, end int64, fn func(atom) error) error {
+	for pos := start; pos+mp4AtomHeaderSize <= end; {
+		if _, err := r.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+
+		var header [16]byte
+
+		if _, err := io.ReadFull(r, header[:mp4AtomHeaderSize]); err != nil {
+			return ErrMalformedMetadata
+		}
+
+		a := atom{
+			typ:   string(header[4:8]),
+			start: pos + mp4AtomHeaderSize,
+		}
+
+		switch size := int64(binary.BigEndian.Uint32(header[:4])); size {
+		case 0:
+			// The atom extends to the end.
+			a.end = end
+		case 1:
+			// 64-bit size.
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return ErrMalformedMetadata
+			}
+			a.start += 8
+			a.end = pos + int64(binary.BigEndian.Uint64(header[8:16]))
+		default:
+			a.end = pos + size
+		}
+
+		if a.end < a.start || a.end > end {
+			return ErrMalformedMetadata
+		}
+
+		if err := fn(a); err != nil {
+			return err
+		}
+
+		pos = a.end
+	}
+
+	return nil
+}
+
+// skipFullAtomHeader skips the version and flags of the meta atom.
+// QuickTime files have no such header, their children follow at once.
+func skipFullAtomHeader(r io.ReadSeeker, meta atom) (atom, error) {
+	if _, err := r.Seek(meta.start, io.SeekStart); err != nil {
+		return atom{}, err
+	}
+
+	var header [8]byte
+
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return atom{}, ErrMalformedMetadata
+	}
+
+	if string(header[4:8]) != "hdlr" {
+		meta.start += 4
+	}
+
+	return meta, nil
+}
+
+// readMP4Data reads the value of the data atom of a metadata item.
+func readMP4Data(r io.ReadSeeker, data atom, field string) (string, error) {
+	size := data.end - data.start
+	if size < 8 {
+		return "", nil
+	}
+	if size > mp4MaxItemSize {
+		return "", ErrMalformedMetadata
+	}
+
+	if _, err := r.Seek(data.start, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", ErrMalformedMetadata
+	}
+
+	// Type indicator and locale.
+	kind, value := binary.BigEndian.Uint32(b[:4])&0xFFFFFF, b[8:]
+
+	switch {
+	case field == "track" || field == "disc":
+		// Reserved, number and total, 16 bits each.
+		if len(value) < 4 {
+			return "", nil
+		}
+		if n := binary.BigEndian.Uint16(value[2:4]); n > 0 {
+			return strconv.Itoa(int(n)), nil
+		}
+		return "", nil
+	case kind == 1:
+		return strings.TrimSpace(string(value)), nil
+	case kind == 2:
+		units := make([]uint16, len(value)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(value[2*i:])
+		}
+		return strings.TrimSpace(string(utf16.Decode(units))), nil
+	}
+
+	return "", nil
+}
+
+// noAtom turns the missing atoms into ErrNoMetadata.
+func noAtom(err error) error {
+	if errors.Is(err, errAtomNotFound) {
+		return ErrNoMetadata
+	}
+	return err
+}
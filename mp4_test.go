@@ -0,0 +1,131 @@
+package musicfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReadMP4(t *testing.T) {
+	ftyp := testAtom("ftyp", []byte("M4A \x00\x00\x00\x00"))
+	mdat := testAtom("mdat", []byte{1, 2, 3, 4})
+	hdlr := testAtom("hdlr", make([]byte, 25))
+
+	ilst := testAtom("ilst",
+		testAtom("\xa9nam", testDataAtom(1, []byte("Song (Live) [Remastered]"))),
+		testAtom("\xa9ART", testDataAtom(1, []byte("Artist feat. Guest"))),
+		testAtom("\xa9alb", testDataAtom(1, []byte("Album"))),
+		testAtom("\xa9day", testDataAtom(1, []byte("2011-05-01T07:00:00Z"))),
+		testAtom("trkn", testDataAtom(0, []byte{0, 0, 0, 3, 0, 12, 0, 0})),
+		testAtom("disk", testDataAtom(0, []byte{0, 0, 0, 1, 0, 2})),
+		testAtom("covr", testDataAtom(13, bytes.Repeat([]byte{0xFF}, 64))),
+	)
+
+	wantInfo := Info{
+		Author: "Artist",
+		Album:  "Album",
+		Work:   "Song",
+		Track:  3,
+		Disc:   1,
+		Year:   2011,
+		Tags:   EmptyTags.Set(Live).Set(Remaster),
+	}
+
+	tests := []struct {
+		name     string
+		file     []byte
+		wantInfo Info
+		wantErr  error
+	}{
+		{
+			name: "itunes",
+			file: concat(
+				ftyp,
+				testAtom("moov",
+					testAtom("mvhd", make([]byte, 100)),
+					testAtom("udta", testAtom("meta", []byte{0, 0, 0, 0}, hdlr, ilst)),
+				),
+				mdat,
+			),
+			wantInfo: wantInfo,
+		},
+		{
+			name: "quicktime meta in moov",
+			file: concat(
+				ftyp,
+				mdat,
+				testAtom("moov", testAtom("meta", hdlr, ilst)),
+			),
+			wantInfo: wantInfo,
+		},
+		{
+			name: "utf-16 and 64-bit size",
+			file: concat(
+				ftyp,
+				testAtom64("moov",
+					testAtom("udta", testAtom("meta", []byte{0, 0, 0, 0}, hdlr, testAtom("ilst",
+						testAtom("\xa9nam", testDataAtom(2, []byte{0x04, 0x1A, 0x04, 0x38, 0x04, 0x3D, 0x04, 0x3E})),
+					))),
+				),
+			),
+			wantInfo: Info{
+				Work: "Кино",
+			},
+		},
+		{
+			name:    "no metadata",
+			file:    concat(ftyp, testAtom("moov", testAtom("mvhd", make([]byte, 100))), mdat),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "not mp4",
+			file:    []byte("ID3\x03\x00\x00\x00\x00\x00\x00"),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "broken size",
+			file:    concat(ftyp, []byte{0, 0, 1, 0, 'm', 'o', 'o', 'v'}),
+			wantErr: ErrMalformedMetadata,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInfo, err := ReadMP4(bytes.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadMP4() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotInfo, tt.wantInfo) {
+				t.Errorf("ReadMP4() = %+v, want %+v", gotInfo, tt.wantInfo)
+			}
+
+			// The format must be detected by the content.
+			gotInfo, err = ReadMetadata(bytes.NewReader(tt.file))
+			if err == nil && !reflect.DeepEqual(gotInfo, tt.wantInfo) {
+				t.Errorf("ReadMetadata() = %+v, want %+v", gotInfo, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func testAtom(typ string, children ...[]byte) []byte {
+	data := concat(children...)
+	b := binary.BigEndian.AppendUint32(nil, uint32(8+len(data)))
+	b = append(b, typ...)
+	return append(b, data...)
+}
+
+func testAtom64(typ string, children ...[]byte) []byte {
+	data := concat(children...)
+	b := binary.BigEndian.AppendUint32(nil, 1)
+	b = append(b, typ...)
+	b = binary.BigEndian.AppendUint64(b, uint64(16+len(data)))
+	return append(b, data...)
+}
+
+func testDataAtom(kind uint32, value []byte) []byte {
+	b := binary.BigEndian.AppendUint32(nil, kind)
+	b = append(b, 0, 0, 0, 0)
+	return testAtom("data", append(b, value...))
+}
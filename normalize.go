@@ -0,0 +1,223 @@
+package musicfile
+
+import (
+	"bytes"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// text is a name prepared for matching. It remembers which bytes
+// of the source every byte came from, so the strings returned
+// to the caller stay faithful to the input.
+type text struct {
+	src  []byte
+	b    []byte
+	from []span
+}
+
+// span of the source bytes. Literal bytes inserted by the parser
+// have a negative start.
+type span struct {
+	start, end int
+}
+
+var literal = span{-1, -1}
+
+// normalize composes the name to NFC and makes dashes, spaces and
+// equivalent letters uniform.
+func normalize(src []byte) text {
+	t := text{
+		src:  src,
+		b:    make([]byte, 0, len(src)),
+		from: make([]span, 0, len(src)),
+	}
+
+	var it norm.Iter
+	it.Init(norm.NFC, src)
+
+	for !it.Done() {
+		start := it.Pos()
+		seg := it.Next()
+		sp := span{start, it.Pos()}
+
+		for len(seg) > 0 {
+			r, size := utf8.DecodeRune(seg)
+			seg = seg[size:]
+
+			r = foldRune(r)
+			if r < 0 {
+				continue
+			}
+
+			n := len(t.b)
+			t.b = utf8.AppendRune(t.b, r)
+
+			for ; n < len(t.b); n++ {
+				t.from = append(t.from, sp)
+			}
+		}
+	}
+
+	return t
+}
+
+// foldRune returns the rune used for matching instead of r,
+// or -1 when r must be dropped.
+func foldRune(r rune) rune {
+	switch {
+	case r == ' ':
+		return r
+	case unicode.IsSpace(r):
+		return ' '
+	case r == '〜', r == '〰', r == '゠':
+		// Japanese wave dashes decorate titles and don't separate them.
+		return r
+	case unicode.Is(unicode.Pd, r), r == '−':
+		return '-'
+	case r >= '！' && r <= '～':
+		// Fullwidth forms of ASCII.
+		return r - '！' + '!'
+	case r == 'ё':
+		return 'е'
+	case r == 'Ё':
+		return 'Е'
+	case r == '\u00ad', r == '\u200b', r == '\u200c', r == '\u200d',
+		r == '\u200e', r == '\u200f', r == '\u2060', r == '\ufeff':
+		// Soft hyphens, zero-width characters and direction marks.
+		return -1
+	}
+	return r
+}
+
+// foldString folds the runes of the vocabulary the same way as names.
+func foldString(s string) string {
+	return string(normalize([]byte(s)).b)
+}
+
+func (t text) slice(i, j int) text {
+	return text{
+		src:  t.src,
+		b:    t.b[i:j:j],
+		from: t.from[i:j:j],
+	}
+}
+
+// source returns the span of the source bytes the bytes from i to j came
+// from, or the literal span if all of them were inserted.
+func (t text) source(i, j int) span {
+	sp := literal
+
+	for _, from := range t.from[i:j] {
+		if from == literal {
+			continue
+		}
+		if sp == literal || from.start < sp.start {
+			sp.start = from.start
+		}
+		if from.end > sp.end {
+			sp.end = from.end
+		}
+	}
+
+	return sp
+}
+
+func (t text) append(u text) text {
+	return text{
+		src:  t.src,
+		b:    append(t.b[:len(t.b):len(t.b)], u.b...),
+		from: append(t.from[:len(t.from):len(t.from)], u.from...),
+	}
+}
+
+func (t text) appendLiteral(s string) text {
+	u := text{
+		b:    []byte(s),
+		from: make([]span, len(s)),
+	}
+
+	for i := range u.from {
+		u.from[i] = literal
+	}
+
+	return t.append(u)
+}
+
+// deleteAll deletes all matches of the expression.
+func (t text) deleteAll(re *regexp.Regexp) text {
+	matches := re.FindAllIndex(t.b, -1)
+	if matches == nil {
+		return t
+	}
+
+	dst := t.slice(0, 0)
+	last := 0
+
+	for _, m := range matches {
+		dst = dst.append(t.slice(last, m[0]))
+		last = m[1]
+	}
+
+	return dst.append(t.slice(last, len(t.b)))
+}
+
+// deleteRune deletes all occurrences of the rune.
+func (t text) deleteRune(r rune) text {
+	if !bytes.ContainsRune(t.b, r) {
+		return t
+	}
+
+	sep := []byte(string(r))
+	dst := t.slice(0, 0)
+
+	for {
+		i := bytes.Index(t.b, sep)
+		if i < 0 {
+			break
+		}
+		dst = dst.append(t.slice(0, i))
+		t = t.slice(i+len(sep), len(t.b))
+	}
+
+	return dst.append(t)
+}
+
+func (t text) trimSpace() text {
+	i, j := 0, len(t.b)
+
+	for i < j && isSpaceByte(t.b[i]) {
+		i++
+	}
+	for j > i && isSpaceByte(t.b[j-1]) {
+		j--
+	}
+
+	return t.slice(i, j)
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// String returns the source bytes of the text.
+func (t text) String() string {
+	var (
+		dst  []byte
+		prev = literal
+	)
+
+	for i, sp := range t.from {
+		switch {
+		case sp == literal:
+			dst = append(dst, t.b[i])
+		case sp != prev:
+			dst = append(dst, t.src[sp.start:sp.end]...)
+		}
+		prev = sp
+	}
+
+	return string(dst)
+}
@@ -0,0 +1,61 @@
+package musicfile
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		wantText string
+		// Source bytes that are left in the text.
+		wantString string
+	}{
+		{
+			name:       "ascii",
+			src:        "a - b",
+			wantText:   "a - b",
+			wantString: "a - b",
+		},
+		{
+			name:       "dashes and spaces",
+			src:        "a\u00a0\u2013\u2003b\u2212c",
+			wantText:   "a - b-c",
+			wantString: "a\u00a0\u2013\u2003b\u2212c",
+		},
+		{
+			name:       "decomposed",
+			src:        "\u0415\u0308лка и\u0306",
+			wantText:   "Елка й",
+			wantString: "\u0415\u0308лка и\u0306",
+		},
+		{
+			name:       "fullwidth",
+			src:        "０１．（ａ）",
+			wantText:   "01.(a)",
+			wantString: "０１．（ａ）",
+		},
+		{
+			name:       "zero width",
+			src:        "\ufeffa\u200bb",
+			wantText:   "ab",
+			wantString: "ab",
+		},
+		{
+			name:       "wave dash",
+			src:        "〜a〜",
+			wantText:   "〜a〜",
+			wantString: "〜a〜",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalize([]byte(tt.src))
+			if string(got.b) != tt.wantText {
+				t.Errorf("normalize() = %q, want %q", got.b, tt.wantText)
+			}
+			if s := got.String(); s != tt.wantString {
+				t.Errorf("normalize().String() = %q, want %q", s, tt.wantString)
+			}
+		})
+	}
+}
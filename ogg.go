@@ -0,0 +1,164 @@
+package musicfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+const (
+	oggPageHeaderSize = 27
+	// oggMaxPacketSize limits the comment packet, which may hold cover art.
+	oggMaxPacketSize = 64 << 20
+)
+
+// ReadOgg reads the comment header of an Ogg Vorbis, Opus or FLAC stream.
+func ReadOgg(r io.ReadSeeker) (Info, error) {
+	m, err := readOgg(r)
+	if err != nil {
+		return Info{}, err
+	}
+	return m.info(), nil
+}
+
+func readOgg(r io.ReadSeeker) (metadata, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return metadata{}, err
+	}
+
+	p := oggPacketReader{r: r}
+
+	ident, err := p.next()
+	if err != nil {
+		return metadata{}, err
+	}
+
+	comment, err := p.next()
+	if err != nil {
+		return metadata{}, ErrMalformedMetadata
+	}
+
+	switch {
+	case bytes.HasPrefix(ident, []byte("\x01vorbis")):
+		comment, err = trimOggHeader(comment, "\x03vorbis")
+	case bytes.HasPrefix(ident, []byte("OpusHead")):
+		comment, err = trimOggHeader(comment, "OpusTags")
+	case bytes.HasPrefix(ident, []byte("\x7fFLAC")):
+		comment, err = trimFLACBlock(comment)
+	default:
+		return metadata{}, ErrNoMetadata
+	}
+
+	if err != nil {
+		return metadata{}, err
+	}
+
+	return parseVorbisComment(comment)
+}
+
+func trimOggHeader(packet []byte, magic string) ([]byte, error) {
+	if !bytes.HasPrefix(packet, []byte(magic)) {
+		return nil, ErrNoMetadata
+	}
+	return packet[len(magic):], nil
+}
+
+// trimFLACBlock trims the metadata block header of the Vorbis comment
+// that follows the FLAC mapping header in Ogg.
+func trimFLACBlock(packet []byte) ([]byte, error) {
+	if len(packet) < 4 {
+		return nil, ErrMalformedMetadata
+	}
+	if packet[0]&0x7F != flacBlockVorbisComment {
+		return nil, ErrNoMetadata
+	}
+	return packet[4:], nil
+}
+
+// oggPacketReader reads the packets of the first logical stream.
+type oggPacketReader struct {
+	r       io.Reader
+	serial  uint32
+	started bool
+	// segments of the current page that aren't read yet.
+	segments []byte
+}
+
+func (p *oggPacketReader) next() ([]byte, error) {
+	var packet []byte
+
+	for {
+		for len(p.segments) > 0 {
+			n := int(p.segments[0])
+			p.segments = p.segments[1:]
+
+			if len(packet)+n > oggMaxPacketSize {
+				return nil, ErrMalformedMetadata
+			}
+
+			start := len(packet)
+			packet = append(packet, make([]byte, n)...)
+
+			if _, err := io.ReadFull(p.r, packet[start:]); err != nil {
+				return nil, ErrMalformedMetadata
+			}
+
+			// A segment shorter than 255 bytes ends the packet.
+			if n < 255 {
+				return packet, nil
+			}
+		}
+
+		if err := p.nextPage(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// nextPage reads the header of the next page of the stream
+// and skips the pages of other streams.
+func (p *oggPacketReader) nextPage() error {
+	for {
+		var header [oggPageHeaderSize]byte
+
+		if _, err := io.ReadFull(p.r, header[:]); err != nil {
+			if !p.started {
+				return noMetadata(err)
+			}
+			return ErrMalformedMetadata
+		}
+
+		if string(header[:4]) != "OggS" {
+			if !p.started {
+				return ErrNoMetadata
+			}
+			return ErrMalformedMetadata
+		}
+
+		serial := binary.LittleEndian.Uint32(header[14:18])
+
+		segments := make([]byte, header[26])
+		if _, err := io.ReadFull(p.r, segments); err != nil {
+			return ErrMalformedMetadata
+		}
+
+		if !p.started {
+			p.started = true
+			p.serial = serial
+		}
+
+		if serial == p.serial {
+			p.segments = segments
+			return nil
+		}
+
+		// Skip the page of another stream.
+		var size int64
+		for _, n := range segments {
+			size += int64(n)
+		}
+		if _, err := io.CopyN(io.Discard, p.r, size); err != nil {
+			return ErrMalformedMetadata
+		}
+	}
+}
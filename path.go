@@ -0,0 +1,149 @@
+package musicfile
+
+import (
+	"bytes"
+)
+
+// PathStyle tells how a file path is split into segments.
+type PathStyle int
+
+const (
+	// PathAuto splits on slashes and backslashes, skips drive letters
+	// and the host and share of UNC paths starting with a backslash.
+	PathAuto PathStyle = iota
+	// PathPOSIX splits on slashes only, so a backslash is a part of a name.
+	PathPOSIX
+	// PathWindows is like PathAuto, but "//host/share" is a UNC path too.
+	PathWindows
+)
+
+// SplitPath splits the file path into segments.
+func SplitPath(filepath []byte, style PathStyle) [][]byte {
+	if style == PathPOSIX {
+		return bytes.Split(filepath, []byte("/"))
+	}
+
+	filepath = trimVolume(filepath, style)
+
+	var path [][]byte
+
+	for {
+		i := bytes.IndexAny(filepath, `/\`)
+		if i < 0 {
+			break
+		}
+		path = append(path, filepath[:i])
+		filepath = filepath[i+1:]
+	}
+
+	return append(path, filepath)
+}
+
+// SplitLibraryPath splits the file path into the segments below the library
+// root, so the names of the root and its parents don't tag the files. The
+// whole path is returned if it isn't below the root. The root matches whole
+// segments only, in any case unless the style is PathPOSIX.
+func SplitLibraryPath(root, filepath []byte, style PathStyle) [][]byte {
+	path := SplitPath(filepath, style)
+	rootPath := SplitPath(root, style)
+
+	equal := bytes.EqualFold
+	if style == PathPOSIX {
+		equal = bytes.Equal
+	}
+
+	// The root on another drive or share.
+	if v := volume(root, style); style != PathPOSIX && len(v) > 0 && !equal(v, volume(filepath, style)) {
+		return path
+	}
+
+	// Trailing separators.
+	for len(rootPath) > 0 && len(rootPath[len(rootPath)-1]) == 0 {
+		rootPath = rootPath[:len(rootPath)-1]
+	}
+
+	if len(rootPath) == 0 || len(rootPath) >= len(path) {
+		return path
+	}
+
+	for i, segment := range rootPath {
+		if !equal(segment, path[i]) {
+			return path
+		}
+	}
+
+	return path[len(rootPath):]
+}
+
+// volume returns the drive letter or the UNC host and share of the path.
+func volume(filepath []byte, style PathStyle) []byte {
+	return filepath[:len(filepath)-len(trimVolume(filepath, style))]
+}
+
+// trimVolume trims the drive letter or the UNC host and share.
+// The leading separator is kept, so the path stays absolute.
+func trimVolume(filepath []byte, style PathStyle) []byte {
+	// Win32 file and device namespaces: `\\?\C:\…`, `\\?\UNC\host\share\…`, `\\.\…`.
+	for _, prefix := range []string{`\\?\`, `\\.\`, `//?/`, `//./`} {
+		if bytes.HasPrefix(filepath, []byte(prefix)) {
+			filepath = filepath[len(prefix):]
+
+			if len(filepath) >= 4 && bytes.EqualFold(filepath[:3], []byte("UNC")) && isSeparator(filepath[3]) {
+				return trimHostShare(filepath[4:])
+			}
+			if !isDrive(filepath) {
+				// A device name like "pipe" or a volume GUID.
+				return trimSegment(filepath)
+			}
+			break
+		}
+	}
+
+	// "C:\Music" and the drive-relative "C:Music\Song.mp3". A lone name
+	// like "A:Side - Song.mp3" keeps its prefix unless the style is Windows.
+	if isDrive(filepath) || hasDriveLetter(filepath) && (style == PathWindows || bytes.ContainsAny(filepath, `/\`)) {
+		return filepath[2:]
+	}
+
+	if len(filepath) >= 2 && isSeparator(filepath[0]) && isSeparator(filepath[1]) {
+		if filepath[0] == '\\' || style == PathWindows {
+			return trimHostShare(filepath[2:])
+		}
+	}
+
+	return filepath
+}
+
+// trimHostShare trims "host\share" and returns the rest of the path.
+func trimHostShare(filepath []byte) []byte {
+	share := trimSegment(filepath)
+	if len(share) == 0 {
+		return nil
+	}
+	return trimSegment(share[1:])
+}
+
+// trimSegment trims the first segment, but keeps the separator after it.
+func trimSegment(filepath []byte) []byte {
+	i := bytes.IndexAny(filepath, `/\`)
+	if i < 0 {
+		return nil
+	}
+	return filepath[i:]
+}
+
+func isDrive(filepath []byte) bool {
+	return hasDriveLetter(filepath) && (len(filepath) == 2 || isSeparator(filepath[2]))
+}
+
+func hasDriveLetter(filepath []byte) bool {
+	if len(filepath) < 2 || filepath[1] != ':' {
+		return false
+	}
+	c := filepath[0] | 0x20
+	return c >= 'a' && c <= 'z'
+}
+
+func isSeparator(c byte) bool {
+	return c == '/' || c == '\\'
+}
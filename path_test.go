@@ -0,0 +1,165 @@
+package musicfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPath(t *testing.T) {
+	type args struct {
+		filepath string
+		style    PathStyle
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "posix",
+			args: args{filepath: `/home/u/a\b.mp3`, style: PathPOSIX},
+			want: []string{"", "home", "u", `a\b.mp3`},
+		},
+		{
+			name: "posix auto",
+			args: args{filepath: "/home/u/a.mp3", style: PathAuto},
+			want: []string{"", "home", "u", "a.mp3"},
+		},
+		{
+			name: "drive",
+			args: args{filepath: `D:\Music\Artist\01 - Song.mp3`, style: PathAuto},
+			want: []string{"", "Music", "Artist", "01 - Song.mp3"},
+		},
+		{
+			name: "drive-like name",
+			args: args{filepath: `A:Side - Song.mp3`, style: PathAuto},
+			want: []string{"A:Side - Song.mp3"},
+		},
+		{
+			name: "windows relative drive name",
+			args: args{filepath: `d:Song.mp3`, style: PathWindows},
+			want: []string{"Song.mp3"},
+		},
+		{
+			name: "relative drive",
+			args: args{filepath: `d:Music\Song.mp3`, style: PathAuto},
+			want: []string{"Music", "Song.mp3"},
+		},
+		{
+			name: "mixed",
+			args: args{filepath: `C:/Music\Artist/Song.mp3`, style: PathWindows},
+			want: []string{"", "Music", "Artist", "Song.mp3"},
+		},
+		{
+			name: "unc",
+			args: args{filepath: `\\nas\music\Artist\Song.mp3`, style: PathAuto},
+			want: []string{"", "Artist", "Song.mp3"},
+		},
+		{
+			name: "unc share only",
+			args: args{filepath: `\\nas\music`, style: PathAuto},
+			want: []string{""},
+		},
+		{
+			name: "double slash auto",
+			args: args{filepath: "//nas/music/Song.mp3", style: PathAuto},
+			want: []string{"", "", "nas", "music", "Song.mp3"},
+		},
+		{
+			name: "double slash windows",
+			args: args{filepath: "//nas/music/Song.mp3", style: PathWindows},
+			want: []string{"", "Song.mp3"},
+		},
+		{
+			name: "long drive",
+			args: args{filepath: `\\?\C:\Music\Song.mp3`, style: PathAuto},
+			want: []string{"", "Music", "Song.mp3"},
+		},
+		{
+			name: "long unc",
+			args: args{filepath: `\\?\UNC\nas\music\Song.mp3`, style: PathAuto},
+			want: []string{"", "Song.mp3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, seg := range SplitPath([]byte(tt.args.filepath), tt.args.style) {
+				got = append(got, string(seg))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitLibraryPath(t *testing.T) {
+	type args struct {
+		root     string
+		filepath string
+		style    PathStyle
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			name: "below root",
+			args: args{root: "/mnt/Music", filepath: "/mnt/Music/Artist/Song.mp3", style: PathAuto},
+			want: []string{"Artist", "Song.mp3"},
+		},
+		{
+			name: "trailing separator",
+			args: args{root: `D:\Music\`, filepath: `D:\Music\Artist\Song.mp3`, style: PathAuto},
+			want: []string{"Artist", "Song.mp3"},
+		},
+		{
+			name: "windows case",
+			args: args{root: `d:\music`, filepath: `D:\Music\Song.mp3`, style: PathWindows},
+			want: []string{"Song.mp3"},
+		},
+		{
+			name: "auto case",
+			args: args{root: `C:\music\`, filepath: `C:\Music\Artist\Song.mp3`, style: PathAuto},
+			want: []string{"Artist", "Song.mp3"},
+		},
+		{
+			name: "another drive",
+			args: args{root: `C:\Music`, filepath: `D:\Music\Song.mp3`, style: PathAuto},
+			want: []string{"", "Music", "Song.mp3"},
+		},
+		{
+			name: "partial segment",
+			args: args{root: "/mnt/Mus", filepath: "/mnt/Music/Song.mp3", style: PathAuto},
+			want: []string{"", "mnt", "Music", "Song.mp3"},
+		},
+		{
+			name: "posix case",
+			args: args{root: "/mnt/music", filepath: "/mnt/Music/Song.mp3", style: PathPOSIX},
+			want: []string{"", "mnt", "Music", "Song.mp3"},
+		},
+		{
+			name: "root is the file",
+			args: args{root: "/mnt/Song.mp3", filepath: "/mnt/Song.mp3", style: PathAuto},
+			want: []string{"", "mnt", "Song.mp3"},
+		},
+		{
+			name: "empty root",
+			args: args{root: "", filepath: "Artist/Song.mp3", style: PathAuto},
+			want: []string{"Artist", "Song.mp3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, seg := range SplitLibraryPath([]byte(tt.args.root), []byte(tt.args.filepath), tt.args.style) {
+				got = append(got, string(seg))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitLibraryPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
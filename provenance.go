@@ -0,0 +1,58 @@
+package musicfile
+
+// TaggedInfo is the info with the tags found in each segment of the path.
+// SegmentTags and SegmentCancels have the tags found and canceled in every
+// segment in the order of the path, the last ones are of the file name.
+// Info.Tags is what is left after each segment cancels the tags of the
+// farther ones and adds its own.
+type TaggedInfo struct {
+	Info
+	SegmentTags    []Tags `json:"segment_tags"`
+	SegmentCancels []Tags `json:"segment_cancels"`
+}
+
+// ExtractTaggedInfo extracts music info from the path like ExtractInfo
+// and keeps the tags of each path segment.
+func ExtractTaggedInfo(filepath []byte) TaggedInfo {
+	return ExtractTaggedPathInfo(SplitPath(filepath, PathAuto))
+}
+
+// ExtractTaggedPathInfo extracts music info from the path segments like
+// ExtractPathInfo and keeps the tags of each segment.
+func ExtractTaggedPathInfo(path [][]byte) TaggedInfo {
+	a := analyzePath(path)
+	t := TaggedInfo{Info: a.info}
+
+	if len(path) == 0 {
+		return t
+	}
+
+	t.SegmentTags = make([]Tags, len(path))
+	t.SegmentCancels = make([]Tags, len(path))
+
+	copy(t.SegmentTags, a.dirTags)
+	copy(t.SegmentCancels, a.dirCancels)
+	t.SegmentTags[len(path)-1] = a.file.Tags
+	t.SegmentCancels[len(path)-1] = a.parse.cancels
+
+	return t
+}
+
+// TagSegments returns the indices of the path segments that have the tag.
+func (t TaggedInfo) TagSegments(tag TagBit) (segments []int) {
+	for i, tags := range t.SegmentTags {
+		if tags.Has(tag) {
+			segments = append(segments, i)
+		}
+	}
+	return segments
+}
+
+// FileTags returns the tags of the file name without the tags
+// of the directories.
+func (t TaggedInfo) FileTags() Tags {
+	if len(t.SegmentTags) == 0 {
+		return EmptyTags
+	}
+	return t.SegmentTags[len(t.SegmentTags)-1]
+}
@@ -0,0 +1,89 @@
+package musicfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTaggedInfo(t *testing.T) {
+	type args struct {
+		filepath string
+	}
+	tests := []struct {
+		name string
+		args args
+		want TaggedInfo
+	}{
+		{
+			name: "grandparent tags",
+			args: args{"Music/Bootlegs/Artist/Artist - Title.mp3"},
+			want: TaggedInfo{
+				Info:           Info{Author: "Artist", Work: "Title", Tags: EmptyTags.Set(Live), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				SegmentTags:    []Tags{EmptyTags, EmptyTags.Set(Live), EmptyTags, EmptyTags},
+				SegmentCancels: []Tags{EmptyTags, EmptyTags, EmptyTags, EmptyTags},
+			},
+		},
+		{
+			name: "tags of each level",
+			args: args{"Music/Artist (Live)/Album (Remastered)/01. Title (Demo).mp3"},
+			want: TaggedInfo{
+				Info: Info{Work: "Title", Track: 1, Tags: EmptyTags.Set(Live).Set(Remaster).Set(Demo), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				SegmentTags: []Tags{
+					EmptyTags,
+					EmptyTags.Set(Live),
+					EmptyTags.Set(Remaster),
+					EmptyTags.Set(Demo),
+				},
+				SegmentCancels: []Tags{EmptyTags, EmptyTags, EmptyTags, EmptyTags},
+			},
+		},
+		{
+			name: "nearer segment cancels",
+			args: args{"Live Bootlegs/1993 (Studio Demos)/01. Song.mp3"},
+			want: TaggedInfo{
+				Info:           Info{Work: "Song", Track: 1, Tags: EmptyTags.Set(Demo), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				SegmentTags:    []Tags{EmptyTags.Set(Live), EmptyTags.Set(Demo), EmptyTags},
+				SegmentCancels: []Tags{EmptyTags, EmptyTags.Set(Live), EmptyTags},
+			},
+		},
+		{
+			name: "nearer unbracketed segment cancels",
+			args: args{"Live Bootlegs/1993 Studio Demos/01. Song.mp3"},
+			want: TaggedInfo{
+				Info:           Info{Work: "Song", Track: 1, Tags: EmptyTags.Set(Demo), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				SegmentTags:    []Tags{EmptyTags.Set(Live), EmptyTags.Set(Demo), EmptyTags},
+				SegmentCancels: []Tags{EmptyTags, EmptyTags.Set(Live), EmptyTags},
+			},
+		},
+		{
+			name: "not a track",
+			args: args{"Bootlegs/cover.jpg"},
+			want: TaggedInfo{
+				Info:           Info{FileExtension: ".jpg", Kind: KindImage},
+				SegmentTags:    []Tags{EmptyTags, EmptyTags},
+				SegmentCancels: []Tags{EmptyTags, EmptyTags},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractTaggedInfo([]byte(tt.args.filepath)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTaggedInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTaggedInfo_TagSegments(t *testing.T) {
+	info := ExtractTaggedInfo([]byte("Bootlegs/Artist/Artist - Title (Live).mp3"))
+
+	if got, want := info.TagSegments(Live), []int{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TaggedInfo.TagSegments() = %v, want %v", got, want)
+	}
+	if got, want := info.FileTags(), EmptyTags.Set(Live); got != want {
+		t.Errorf("TaggedInfo.FileTags() = %v, want %v", got, want)
+	}
+	if got := info.TagSegments(Remix); got != nil {
+		t.Errorf("TaggedInfo.TagSegments() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,108 @@
+package musicfile
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Synthetic values for the round trip. Brackets in the work are qualifiers
+// and are not a part of it, so only the albums have them. Works have spaced
+// dashes only when the author is known from the directories.
+var (
+	roundTripAuthors = []string{
+		"Artist", "The Beatles", "Jay-Z", "Би-2", "Кино", "Сплин", "Мумий Тролль",
+		"Author - Band", "Сектор Газа - Band", "10cc", "AC_DC", "Guns N' Roses",
+	}
+	roundTripWorks = []string{
+		"Song", "Night-Time", "Осень", "Звезда по имени Солнце", "Часть 2",
+		"Ёлка", "What's Up", "Song No. 9",
+	}
+	roundTripDashedWorks = []string{
+		"Part One - Part Two", "Песня - Часть 1",
+	}
+	roundTripAlbums = []string{
+		"Album", "Группа крови", "Чёрный альбом", "Greatest Hits [Part 2]",
+		"Album (Deluxe Edition)", "Side-B",
+	}
+	roundTripExts = []string{".mp3", ".flac", ".ogg", ".m4a", ".opus"}
+)
+
+func TestFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		album    bool
+		year     bool
+		dashes   bool
+	}{
+		{
+			name:     "flat",
+			template: "{track:02} - {author} - {work}{tags: ({names})}{ext}",
+		},
+		{
+			name:     "author and album directories",
+			template: "{author}/{album}/{track:02} - {author} - {work}{tags: ({names})}{ext}",
+			album:    true,
+			dashes:   true,
+		},
+		{
+			name:     "album directory with year",
+			template: "{author} - {year:{year} - }{album}/{track:{track:02}. }{author} - {work}{tags: [{names}]}{ext}",
+			album:    true,
+			year:     true,
+			dashes:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(1))
+
+			for i := 0; i < 500; i++ {
+				info := randomInfo(rnd, tt.album, tt.year, tt.dashes)
+
+				path, err := Format(info, tt.template)
+				if err != nil {
+					t.Fatalf("Format(%+v) error = %v", info, err)
+				}
+
+				got := ExtractInfo([]byte(path))
+
+				if got.Author != info.Author || got.Work != info.Work || got.Album != info.Album ||
+					got.Track != info.Track || got.Year != info.Year || got.Tags != info.Tags {
+					t.Fatalf("ExtractInfo(%q) = %+v, want %+v", path, got, info)
+				}
+			}
+		})
+	}
+}
+
+func randomInfo(rnd *rand.Rand, album, year, dashes bool) Info {
+	pick := func(values []string) string {
+		return values[rnd.Intn(len(values))]
+	}
+
+	info := Info{
+		Author:        pick(roundTripAuthors),
+		Work:          pick(roundTripWorks),
+		Track:         rnd.Intn(100),
+		FileExtension: pick(roundTripExts),
+	}
+
+	if dashes && rnd.Intn(4) == 0 {
+		info.Work = pick(roundTripDashedWorks)
+	}
+	if album {
+		info.Album = pick(roundTripAlbums)
+	}
+	if year && rnd.Intn(2) == 0 {
+		info.Year = 1960 + rnd.Intn(60)
+	}
+
+	for bit := TagBit(0); bit < bits; bit++ {
+		if rnd.Intn(8) == 0 {
+			info.Tags = info.Tags.Set(bit)
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,172 @@
+package musicfile
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"runtime"
+	"sync"
+)
+
+// ScanResult is the info of an audio file found by Scan, or the error of
+// walking the directory or reading the file at the path. The info of the
+// path is kept when the embedded metadata can't be read.
+type ScanResult struct {
+	Path string
+	Info Info
+	Err  error
+}
+
+// Scanner walks music libraries and extracts the info of the audio files.
+type Scanner struct {
+	// Workers is the number of files extracted concurrently,
+	// runtime.GOMAXPROCS by default.
+	Workers int
+	// Embedded makes the scanner read the embedded metadata of the files
+	// and merge it with the info of the path like ExtractFileInfo.
+	// Only the files that implement io.Seeker are read.
+	Embedded bool
+	// ExcludeRoot leaves root out of the paths the info is extracted from,
+	// like ExtractLibraryInfo, so the name of root doesn't tag the files.
+	ExcludeRoot bool
+}
+
+// Scan walks the tree of fsys at root with the default Scanner.
+func Scan(ctx context.Context, fsys fs.FS, root string) <-chan ScanResult {
+	return Scanner{}.Scan(ctx, fsys, root)
+}
+
+// Scan walks the tree of fsys at root and sends the results of the audio
+// files in no particular order. Other files and unfinished downloads, like
+// "Song.mp3.part", are skipped. The errors are sent as results and the walk
+// goes on. The channel is closed when the walk is done or the context is
+// canceled.
+func (s Scanner) Scan(ctx context.Context, fsys fs.FS, root string) <-chan ScanResult {
+	workers := s.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	results := make(chan ScanResult)
+	paths := make(chan string)
+
+	send := func(r ScanResult) bool {
+		select {
+		case results <- r:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	// The walker sends the errors too, so results is closed after it.
+	wg.Add(workers + 1)
+
+	go func() {
+		defer wg.Done()
+		defer close(paths)
+
+		_ = fs.WalkDir(fsys, root, func(path string, d fs.DirEntry, err error) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if err != nil {
+				if !send(ScanResult{Path: path, Err: err}) {
+					return ctx.Err()
+				}
+				return nil
+			}
+
+			name := []byte(d.Name())
+			if d.IsDir() || isTemporary(name) || Classify(name) != KindAudio {
+				return nil
+			}
+
+			select {
+			case paths <- path:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for path := range paths {
+				info, err := s.extract(fsys, root, path)
+				if !send(ScanResult{Path: path, Info: info, Err: err}) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// Walk walks the tree of fsys at root like Scan and calls fn for each
+// result from a single goroutine. It stops at the first error of fn and
+// returns it, or returns the error of the context when it is canceled.
+func (s Scanner) Walk(ctx context.Context, fsys fs.FS, root string, fn func(ScanResult) error) error {
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := s.Scan(scanCtx, fsys, root)
+
+	for r := range results {
+		if err := fn(r); err != nil {
+			cancel()
+			for range results {
+			}
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+func (s Scanner) extract(fsys fs.FS, root, path string) (Info, error) {
+	var info Info
+	if s.ExcludeRoot {
+		info = ExtractPathInfo(SplitLibraryPath([]byte(root), []byte(path), PathPOSIX))
+	} else {
+		info = ExtractInfoStyle([]byte(path), PathPOSIX)
+	}
+
+	if !s.Embedded {
+		return info, nil
+	}
+
+	f, err := fsys.Open(path)
+	if err != nil {
+		return info, err
+	}
+	defer f.Close()
+
+	// The tags may be at the end, so the file isn't buffered.
+	r, ok := f.(io.ReadSeeker)
+	if !ok {
+		return info, nil
+	}
+
+	embedded, err := ReadMetadata(r)
+	if errors.Is(err, ErrNoMetadata) {
+		return info, nil
+	}
+	if err != nil {
+		return info, err
+	}
+
+	return mergeInfo(info, embedded), nil
+}
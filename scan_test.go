@@ -0,0 +1,239 @@
+package musicfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestScan(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Artist/Album/01 - Artist - Song.mp3":          {Data: testID3v2Tag(4, 0, testID3Frame(4, "TIT2", utf8Text("Real Song")))},
+		"Artist/Album/02 - Artist - Other (Live).flac": {},
+		"Artist/Album/cover.jpg":                       {},
+		"Artist/Album/Artist - Album.cue":              {},
+		"Artist/Album/broken.mp3":                      {Data: []byte("ID3\x04\x00\x00\x00\x00\x7f\x7f")},
+		"Artist/Album/03 - Artist - Next.mp3.part":     {},
+		"Artist/Album/04 - Artist - Next.crdownload":   {},
+		"readme.txt": {},
+	}
+
+	type args struct {
+		scanner Scanner
+		root    string
+	}
+	tests := []struct {
+		name string
+		args args
+		want []ScanResult
+	}{
+		{
+			name: "path",
+			args: args{scanner: Scanner{Workers: 2}, root: "."},
+			want: []ScanResult{
+				{
+					Path: "Artist/Album/01 - Artist - Song.mp3",
+					Info: Info{Author: "Artist", Album: "Album", Work: "Song", Track: 1, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				},
+				{
+					Path: "Artist/Album/02 - Artist - Other (Live).flac",
+					Info: Info{Author: "Artist", Album: "Album", Work: "Other", Track: 2, Tags: EmptyTags.Set(Live), FileExtension: ".flac", Family: FamilyFLAC, Kind: KindAudio},
+				},
+				{
+					Path: "Artist/Album/broken.mp3",
+					Info: Info{Work: "broken", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				},
+			},
+		},
+		{
+			name: "embedded",
+			args: args{scanner: Scanner{Embedded: true}, root: "Artist"},
+			want: []ScanResult{
+				{
+					Path: "Artist/Album/01 - Artist - Song.mp3",
+					Info: Info{Author: "Artist", Album: "Album", Work: "Real Song", Track: 1, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				},
+				{
+					Path: "Artist/Album/02 - Artist - Other (Live).flac",
+					Info: Info{Author: "Artist", Album: "Album", Work: "Other", Track: 2, Tags: EmptyTags.Set(Live), FileExtension: ".flac", Family: FamilyFLAC, Kind: KindAudio},
+				},
+				{
+					Path: "Artist/Album/broken.mp3",
+					Info: Info{Work: "broken", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+					Err:  ErrMalformedMetadata,
+				},
+			},
+		},
+		{
+			name: "exclude root",
+			args: args{scanner: Scanner{ExcludeRoot: true}, root: "Artist/Album"},
+			want: []ScanResult{
+				{
+					Path: "Artist/Album/01 - Artist - Song.mp3",
+					Info: Info{Author: "Artist", Work: "Song", Track: 1, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				},
+				{
+					Path: "Artist/Album/02 - Artist - Other (Live).flac",
+					Info: Info{Author: "Artist", Work: "Other", Track: 2, Tags: EmptyTags.Set(Live), FileExtension: ".flac", Family: FamilyFLAC, Kind: KindAudio},
+				},
+				{
+					Path: "Artist/Album/broken.mp3",
+					Info: Info{Work: "broken", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				},
+			},
+		},
+		{
+			name: "missing root",
+			args: args{root: "Other"},
+			want: []ScanResult{
+				{Path: "Other", Err: fs.ErrNotExist},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []ScanResult
+
+			for r := range tt.args.scanner.Scan(context.Background(), fsys, tt.args.root) {
+				got = append(got, r)
+			}
+
+			sort.Slice(got, func(i, j int) bool { return got[i].Path < got[j].Path })
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("Scan() = %+v, want %+v", got, tt.want)
+			}
+
+			for i := range got {
+				if !errors.Is(got[i].Err, tt.want[i].Err) {
+					t.Errorf("Scan() error = %v, want %v", got[i].Err, tt.want[i].Err)
+				}
+				got[i].Err = tt.want[i].Err
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanner_Scan_notSeekable(t *testing.T) {
+	fsys := notSeekableFS{fstest.MapFS{
+		"Artist - Song.mp3": {Data: testID3v2Tag(4, 0, testID3Frame(4, "TIT2", utf8Text("Real Song")))},
+	}}
+
+	var got []ScanResult
+	for r := range (Scanner{Embedded: true}).Scan(context.Background(), fsys, ".") {
+		got = append(got, r)
+	}
+
+	want := []ScanResult{
+		{
+			Path: "Artist - Song.mp3",
+			Info: Info{Author: "Artist", Work: "Song", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scan() = %+v, want %+v", got, want)
+	}
+}
+
+// notSeekableFS hides the Seek method of the files.
+type notSeekableFS struct {
+	fstest.MapFS
+}
+
+func (fsys notSeekableFS) Open(name string) (fs.File, error) {
+	f, err := fsys.MapFS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := f.(fs.ReadDirFile); ok {
+		return f, nil
+	}
+	return struct{ fs.File }{f}, nil
+}
+
+func TestScanner_Scan_canceled(t *testing.T) {
+	fsys := brokenDirsFS{fstest.MapFS{}}
+	for i := 0; i < 100; i++ {
+		fsys.MapFS[fmt.Sprintf("Song %d.mp3", i)] = &fstest.MapFile{}
+		fsys.MapFS[fmt.Sprintf("d%d/Song.mp3", i)] = &fstest.MapFile{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := Scanner{Workers: 1}.Scan(ctx, fsys, ".")
+
+	// The worker stops on the next file while the walk reaches the errors.
+	<-results
+	cancel()
+
+	// The walk still sends errors, results must be closed only after it.
+	for range results {
+	}
+}
+
+// brokenDirsFS fails to open the directories below the root.
+type brokenDirsFS struct {
+	fstest.MapFS
+}
+
+func (fsys brokenDirsFS) Open(name string) (fs.File, error) {
+	if name != "." && !strings.Contains(name, "/") && !strings.HasSuffix(name, ".mp3") {
+		return nil, fs.ErrPermission
+	}
+	return fsys.MapFS.Open(name)
+}
+
+func TestScanner_Walk(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for _, name := range []string{"a.mp3", "b.mp3", "c.mp3", "d/e.mp3", "d/f.mp3"} {
+		fsys[name] = &fstest.MapFile{}
+	}
+
+	t.Run("stop", func(t *testing.T) {
+		errStop := errors.New("stop")
+
+		n := 0
+
+		err := Scanner{Workers: 2}.Walk(context.Background(), fsys, ".", func(ScanResult) error {
+			n++
+			return errStop
+		})
+		if !errors.Is(err, errStop) || n != 1 {
+			t.Errorf("Walk() error = %v after %d results, want %v after 1", err, n, errStop)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := Scanner{}.Walk(ctx, fsys, ".", func(ScanResult) error {
+			return nil
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Walk() error = %v, want %v", err, context.Canceled)
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		n := 0
+
+		err := Scanner{}.Walk(context.Background(), fsys, ".", func(ScanResult) error {
+			n++
+			return nil
+		})
+		if err != nil || n != len(fsys) {
+			t.Errorf("Walk() error = %v after %d results, want nil after %d", err, n, len(fsys))
+		}
+	})
+}
@@ -0,0 +1,172 @@
+package musicfile
+
+import (
+	"math"
+	"strings"
+)
+
+// ScoredInfo is the info with the confidence of each field and each tag,
+// from 0 for a wild guess to 1 for a certain fact. The fields that are
+// empty have no confidence.
+type ScoredInfo struct {
+	Info
+	Confidence    map[Field]float64  `json:"confidence,omitempty"`
+	TagConfidence map[TagBit]float64 `json:"tag_confidence,omitempty"`
+}
+
+// Confidence of the author and the work by the separator between them.
+var splitConfidence = map[splitKind]float64{
+	splitNone:         0.8,
+	splitDash:         0.9,
+	splitDashes:       0.5,
+	splitUnspaced:     0.7,
+	splitUnspacedMany: 0.4,
+	splitInserted:     0.5,
+	splitQuotes:       0.9,
+	splitKnown:        0.95,
+}
+
+const (
+	// The track number followed by a dot or a dash, like "01. " and "01 - ",
+	// and the one followed by spaces only, like "01 ".
+	trackConfidence      = 0.9
+	trackSpaceConfidence = 0.6
+	// The album named by the parent of the author directory, like "Artist/Album",
+	// and by the parent directory after the author, like "Artist - Album".
+	albumConfidence       = 0.8
+	albumPrefixConfidence = 0.9
+	yearConfidence        = 0.9
+	// The tags found by the strong keywords like "live",
+	// by the weak keywords like "alt" and by the other rules.
+	keywordConfidence     = 0.9
+	weakKeywordConfidence = 0.5
+	ruleConfidence        = 0.8
+	// The tags of the directories may not apply to every file.
+	dirTagsFactor = 0.8
+	// The names repaired from a broken encoding may be repaired wrong.
+	repairedFactor = 0.9
+)
+
+// ExtractScoredInfo extracts music info from the path like ExtractInfo
+// and scores the confidence of each field and tag.
+func ExtractScoredInfo(filepath []byte) ScoredInfo {
+	return ExtractScoredPathInfo(SplitPath(filepath, PathAuto))
+}
+
+// ExtractScoredPathInfo extracts music info from the path segments like
+// ExtractPathInfo and scores the confidence of each field and tag.
+func ExtractScoredPathInfo(path [][]byte) ScoredInfo {
+	return scoreAnalysis(analyzePath(path))
+}
+
+// scoreAnalysis scores the fields and the tags of the analysed path.
+func scoreAnalysis(a pathAnalysis) ScoredInfo {
+	s := ScoredInfo{Info: a.info}
+
+	textFactor := 1.0
+	if s.Repaired {
+		textFactor = repairedFactor
+	}
+
+	split := splitConfidence[a.parse.split]
+
+	if s.Author != "" {
+		s.score(FieldAuthor, split*textFactor)
+	}
+	if s.Work != "" {
+		s.score(FieldWork, split*textFactor)
+	}
+
+	if s.Track != 0 {
+		if a.parse.trackSeparated {
+			s.score(FieldTrack, trackConfidence)
+		} else {
+			s.score(FieldTrack, trackSpaceConfidence)
+		}
+	}
+
+	if s.Album != "" {
+		n := len(a.dirnames)
+		if n >= 2 && strings.EqualFold(string(a.dirnames[n-2].trimSpace().b), foldString(s.Author)) {
+			s.score(FieldAlbum, albumConfidence*textFactor)
+		} else {
+			s.score(FieldAlbum, albumPrefixConfidence*textFactor)
+		}
+	}
+
+	if s.Year != 0 {
+		s.score(FieldYear, yearConfidence)
+	}
+
+	s.scoreTags(a.file.Tags, a.parse.tags, 1)
+	for _, matches := range a.dirMatches {
+		s.scoreTags(a.dir.Tags, matches, dirTagsFactor)
+	}
+
+	// The tags are as certain as the least certain of them.
+	for _, c := range s.TagConfidence {
+		if tags, ok := s.Confidence[FieldTags]; !ok || c < tags {
+			s.score(FieldTags, c)
+		}
+	}
+
+	return s
+}
+
+// Confident returns the info without the fields and the tags
+// that have confidence below the threshold.
+func (s ScoredInfo) Confident(threshold float64) Info {
+	info := s.Info
+
+	for _, f := range []Field{FieldAuthor, FieldAlbum, FieldWork, FieldTrack, FieldDisc, FieldYear} {
+		if s.Confidence[f] < threshold {
+			info = setField(info, Info{}, f)
+		}
+	}
+
+	for tag, c := range s.TagConfidence {
+		if c < threshold {
+			info.Tags = info.Tags.Del(tag)
+		}
+	}
+
+	return info
+}
+
+func (s *ScoredInfo) score(f Field, c float64) {
+	if s.Confidence == nil {
+		s.Confidence = make(map[Field]float64)
+	}
+	s.Confidence[f] = round(c)
+}
+
+// scoreTags scores the tags the matches found, a tag keeps
+// the highest confidence of all its matches.
+func (s *ScoredInfo) scoreTags(tags Tags, matches []tagMatch, factor float64) {
+	for _, m := range matches {
+		if m.deleted || !tags.Has(m.tag) {
+			continue
+		}
+
+		c := ruleConfidence
+		if m.rule == ruleKeyword {
+			c = keywordConfidence
+			if m.weak() {
+				c = weakKeywordConfidence
+			}
+		}
+
+		c = round(c * factor)
+
+		if s.TagConfidence == nil {
+			s.TagConfidence = make(map[TagBit]float64)
+		}
+		if c > s.TagConfidence[m.tag] {
+			s.TagConfidence[m.tag] = c
+		}
+	}
+}
+
+func round(c float64) float64 {
+	return math.Round(c*100) / 100
+}
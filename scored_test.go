@@ -0,0 +1,143 @@
+package musicfile
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestExtractScoredInfo(t *testing.T) {
+	type args struct {
+		filepath string
+	}
+	tests := []struct {
+		name string
+		args args
+		want ScoredInfo
+	}{
+		{
+			name: "single dash",
+			args: args{"Music/Artist - Title (Live).mp3"},
+			want: ScoredInfo{
+				Info:          Info{Author: "Artist", Work: "Title", Tags: EmptyTags.Set(Live), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence:    map[Field]float64{FieldAuthor: 0.9, FieldWork: 0.9, FieldTags: 0.9},
+				TagConfidence: map[TagBit]float64{Live: 0.9},
+			},
+		},
+		{
+			name: "several dashes",
+			args: args{"a - b - c.mp3"},
+			want: ScoredInfo{
+				Info:       Info{Author: "a - b", Work: "c", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence: map[Field]float64{FieldAuthor: 0.5, FieldWork: 0.5},
+			},
+		},
+		{
+			name: "unspaced dash",
+			args: args{"Artist-Title.mp3"},
+			want: ScoredInfo{
+				Info:       Info{Author: "Artist", Work: "Title", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence: map[Field]float64{FieldAuthor: 0.7, FieldWork: 0.7},
+			},
+		},
+		{
+			name: "underscores",
+			args: args{"Some_Artist__Song.mp3"},
+			want: ScoredInfo{
+				Info:       Info{Author: "Some Artist", Work: "Song", FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence: map[Field]float64{FieldAuthor: 0.5, FieldWork: 0.5},
+			},
+		},
+		{
+			name: "transliterated weak keyword",
+			args: args{"01 Song (klub).mp3"},
+			want: ScoredInfo{
+				Info:          Info{Work: "Song", Track: 1, Tags: EmptyTags.Set(Remix), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence:    map[Field]float64{FieldWork: 0.8, FieldTrack: 0.6, FieldTags: 0.5},
+				TagConfidence: map[TagBit]float64{Remix: 0.5},
+			},
+		},
+		{
+			name: "weak keyword",
+			args: args{"01 Song (bass).mp3"},
+			want: ScoredInfo{
+				Info:          Info{Work: "Song", Track: 1, Tags: EmptyTags.Set(Remix), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence:    map[Field]float64{FieldWork: 0.8, FieldTrack: 0.6, FieldTags: 0.5},
+				TagConfidence: map[TagBit]float64{Remix: 0.5},
+			},
+		},
+		{
+			name: "known author and album",
+			args: args{"Artist/Album/01. Artist - Title.mp3"},
+			want: ScoredInfo{
+				Info:       Info{Author: "Artist", Album: "Album", Work: "Title", Track: 1, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence: map[Field]float64{FieldAuthor: 0.95, FieldAlbum: 0.8, FieldWork: 0.95, FieldTrack: 0.9},
+			},
+		},
+		{
+			name: "album and year prefix",
+			args: args{"Artist - 1999 - Album/02 - Artist - Title.mp3"},
+			want: ScoredInfo{
+				Info:       Info{Author: "Artist", Album: "Album", Work: "Title", Track: 2, Year: 1999, FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence: map[Field]float64{FieldAuthor: 0.95, FieldAlbum: 0.9, FieldWork: 0.95, FieldTrack: 0.9, FieldYear: 0.9},
+			},
+		},
+		{
+			name: "directory tags",
+			args: args{"Artist (Live at Wembley)/Artist - Title (Remix).mp3"},
+			want: ScoredInfo{
+				Info:          Info{Author: "Artist", Work: "Title", Tags: EmptyTags.Set(Live).Set(Remix), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio},
+				Confidence:    map[Field]float64{FieldAuthor: 0.9, FieldWork: 0.9, FieldTags: 0.72},
+				TagConfidence: map[TagBit]float64{Live: 0.72, Remix: 0.9},
+			},
+		},
+		{
+			name: "not a track",
+			args: args{"Music/cover.jpg"},
+			want: ScoredInfo{
+				Info: Info{FileExtension: ".jpg", Kind: KindImage},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractScoredInfo([]byte(tt.args.filepath)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractScoredInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoredInfo_Confident(t *testing.T) {
+	s := ExtractScoredInfo([]byte("01 Song (bass) (Live).mp3"))
+
+	want := Info{Work: "Song", Tags: EmptyTags.Set(Live), FileExtension: ".mp3", Family: FamilyMPEG, Kind: KindAudio}
+
+	if got := s.Confident(0.8); !reflect.DeepEqual(got, want) {
+		t.Errorf("ScoredInfo.Confident() = %+v, want %+v", got, want)
+	}
+}
+
+func TestScoredInfo_JSON(t *testing.T) {
+	s := ExtractScoredInfo([]byte("Artist - Title (Live).mp3"))
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"author":"Artist","work":"Title","tags":1,"file_extension":".mp3","family":"mpeg","kind":"audio",` +
+		`"confidence":{"author":0.9,"tags":0.9,"work":0.9},"tag_confidence":{"Live":0.9}}`
+
+	if string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	var got ScoredInfo
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, s) {
+		t.Errorf("json.Unmarshal() = %+v, want %+v", got, s)
+	}
+}
@@ -0,0 +1,182 @@
+package musicfile
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Rules that find the tags in names.
+const (
+	ruleLiveAt         = "live at"
+	ruleFilenameLiveAt = "filename live at"
+	ruleDemos          = "demos"
+	ruleInterview      = "interview"
+	ruleCoverBy        = "cover by"
+	ruleKeyword        = "keyword"
+	ruleOriginalMix    = "original mix"
+	ruleMixBy          = "mix by"
+	ruleStudio         = "studio"
+	ruleAlbumVersion   = "album version"
+	ruleOriginal       = "original"
+)
+
+// weakKeywordRe matches the keywords that often mean something else,
+// like "alt" and "bass" in brackets, with the transliterations of the
+// Cyrillic ones like the keyword matchers have.
+var weakKeywordRe = regexp.MustCompile("(?i)^(?:" + strings.Join(withTranslit([]string{
+	"alt", "bass", "boost", "disco", "club",
+	"orch", "acoust", "instrument", "video",
+	"бас", "клуб", "видео", "минус",
+}, nil), "|") + ")$")
+
+// tagMatch is a tag found in a name by a rule. The deleting rules
+// take the tag off, like "original mix" takes off Remix.
+type tagMatch struct {
+	tag        TagBit
+	rule       string
+	text       string
+	start, end int
+	deleted    bool
+}
+
+// weak reports whether the keyword that found the tag is weak.
+func (m tagMatch) weak() bool {
+	return m.rule == ruleKeyword && weakKeywordRe.MatchString(m.text)
+}
+
+// matchTags finds the tags of the name. Filenames and directories
+// differ in the rule that finds live recordings, only directories
+// are named after their demos.
+func matchTags(name []byte, liveRe *regexp.Regexp) (tags Tags, matches []tagMatch) {
+	add := func(tag TagBit, rule string, loc []int, deleted bool) {
+		matches = append(matches, tagMatch{
+			tag:     tag,
+			rule:    rule,
+			text:    string(name[loc[0]:loc[1]]),
+			start:   loc[0],
+			end:     loc[1],
+			deleted: deleted,
+		})
+		if deleted {
+			tags = tags.Del(tag)
+		} else {
+			tags = tags.Set(tag)
+		}
+	}
+
+	if loc := liveRe.FindIndex(name); loc != nil {
+		rule := ruleLiveAt
+		if liveRe == tagsFilenameLiveAtRe {
+			rule = ruleFilenameLiveAt
+		}
+		add(Live, rule, loc, false)
+	}
+	if loc := tagsDemosRe.FindIndex(name); loc != nil && liveRe == tagsLiveAtRe {
+		add(Demo, ruleDemos, trimQualifier(name, loc), false)
+	}
+	if loc := tagsInterviewWithRe.FindIndex(name); loc != nil {
+		add(Interview, ruleInterview, loc, false)
+	}
+	if loc := tagsCoverBy.FindIndex(name); loc != nil {
+		add(Cover, ruleCoverBy, loc, false)
+	}
+
+	groupNames := tagsRe.SubexpNames()
+
+	for _, p := range parenthesesRe.FindAllIndex(name, -1) {
+		for _, match := range tagsRe.FindAllSubmatchIndex(name[p[0]:p[1]], -1) {
+			for groupIdx := 1; groupIdx < len(match)/2; groupIdx++ {
+				start, end := match[2*groupIdx], match[2*groupIdx+1]
+				if start < 0 || groupNames[groupIdx] == "" {
+					continue
+				}
+
+				tag, ok := nameToTag[groupNames[groupIdx]]
+				if !ok {
+					continue
+				}
+
+				add(tag, ruleKeyword, []int{p[0] + start, p[0] + end}, false)
+			}
+		}
+	}
+
+	if loc := tagsOriginalMixRe.FindIndex(name); loc != nil {
+		add(Remix, ruleOriginalMix, loc, true)
+	}
+	if loc := tagsMixBy.FindIndex(name); loc != nil {
+		add(Remix, ruleMixBy, loc, false)
+	}
+
+	return tags, matches
+}
+
+// matchCancels finds the tags the name cancels in the farther segments
+// of the path, like "Studio" cancels Live of "Live Bootlegs". Only the
+// directories cancel by their whole name, a file may be titled "Original".
+func matchCancels(name []byte, dir bool) (cancels Tags, matches []tagMatch) {
+	cancel := func(rule string, re *regexp.Regexp, tags ...TagBit) {
+		loc := re.FindSubmatchIndex(name)
+		if loc == nil {
+			return
+		}
+		if standalone := re.SubexpIndex(groupStandalone); !dir && loc[2*standalone] >= 0 {
+			return
+		}
+
+		// Leave the brackets and the separators out of the match.
+		loc = trimQualifier(name, loc[:2])
+
+		for _, tag := range tags {
+			matches = append(matches, tagMatch{
+				tag:     tag,
+				rule:    rule,
+				text:    string(name[loc[0]:loc[1]]),
+				start:   loc[0],
+				end:     loc[1],
+				deleted: true,
+			})
+			cancels = cancels.Set(tag)
+		}
+	}
+
+	cancel(ruleStudio, tagsStudioRe, Live)
+	cancel(ruleAlbumVersion, tagsAlbumVersionRe, Live, Remix, Radio)
+	cancel(ruleOriginal, tagsOriginalRe, Remix, Cover)
+
+	return cancels, matches
+}
+
+// trimQualifier trims the span of the match to its letters and digits.
+func trimQualifier(name []byte, loc []int) []int {
+	start, end := loc[0], loc[1]
+
+	for start < end {
+		r, size := utf8.DecodeRune(name[start:end])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			break
+		}
+		start += size
+	}
+	for end > start {
+		r, size := utf8.DecodeLastRune(name[start:end])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			break
+		}
+		end -= size
+	}
+
+	return []int{start, end}
+}
+
+// inheritTags returns the tags the segments of the path leave, from the
+// farthest to the nearest. A segment cancels the tags of the farther
+// ones before it adds its own.
+func inheritTags(tags, cancels []Tags) (inherited Tags) {
+	for i := range tags {
+		inherited = inherited&^cancels[i] | tags[i]
+	}
+	return inherited
+}
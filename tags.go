@@ -129,3 +129,16 @@ func (tb TagBit) String() string {
 	}
 	return "unknown"
 }
+
+func (tb TagBit) MarshalText() ([]byte, error) {
+	return []byte(tb.String()), nil
+}
+
+func (tb *TagBit) UnmarshalText(text []byte) error {
+	tag, ok := nameToTag[string(text)]
+	if !ok {
+		return fmt.Errorf("tag with name '%s' not found", text)
+	}
+	*tb = tag
+	return nil
+}
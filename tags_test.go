@@ -37,3 +37,47 @@ func TestTags_Intersects(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractFilenameTags_translit(t *testing.T) {
+	type args struct {
+		filename []byte
+	}
+	tests := []struct {
+		name string
+		args args
+		want Tags
+	}{
+		{
+			name: "base",
+			args: args{filename: []byte("Artist - Song (Base)")},
+			want: EmptyTags,
+		},
+		{
+			name: "basic",
+			args: args{filename: []byte("Artist - Song (Basic)")},
+			want: EmptyTags,
+		},
+		{
+			name: "basel",
+			args: args{filename: []byte("Artist - Song (Basel)")},
+			want: EmptyTags,
+		},
+		{
+			name: "volapuk inside a word",
+			args: args{filename: []byte("Artist - Song (Mp4ernovik)")},
+			want: EmptyTags,
+		},
+		{
+			name: "cyrillic bass",
+			args: args{filename: []byte("Artist - Song (бас)")},
+			want: EmptyTags.Set(Remix),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractFilenameTags(tt.args.filename); got != tt.want {
+				t.Errorf("ExtractFilenameTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
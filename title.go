@@ -0,0 +1,180 @@
+package musicfile
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TitleInfo is music info extracted from the title, artist and album
+// strings of a database or of embedded metadata.
+type TitleInfo struct {
+	Info
+	// Featured are the artists after "feat." in the artist or the title.
+	Featured []string `json:"featured,omitempty"`
+	// Remixer is the artist of the remix, like "B" in "Song (B Remix)".
+	Remixer string `json:"remixer,omitempty"`
+	// Qualifiers are the versions of the title, like "Live at Wembley"
+	// in "Song (Live at Wembley)" or "2011 Remaster" in "Song - 2011 Remaster".
+	Qualifiers []string `json:"qualifiers,omitempty"`
+}
+
+// versionWords are the words of remix names that don't name a remixer,
+// like "Extended Remix".
+var versionWords = map[string]bool{
+	"original": true, "extended": true, "radio": true, "club": true, "album": true,
+	"single": true, "short": true, "long": true, "official": true, "vocal": true,
+	"instrumental": true, "dub": true, "main": true, "clean": true, "explicit": true,
+	"edit": true, "mix": true, "remix": true, "version": true, "the": true,
+	"inch": true, "full": true, "alternative": true, "alternate": true, "tv": true,
+}
+
+// ExtractTitleInfo extracts music info from the separate title, artist and
+// album strings. Unlike ExtractInfo, it doesn't split the title into the
+// author and the work by " - " and doesn't look for a file extension.
+func ExtractTitleInfo(title, artist, album string) (info TitleInfo) {
+	name, repaired := prepare([]byte(title))
+	info.Repaired = repaired
+
+	info.Tags = filenameTags(name.b)
+
+	for _, loc := range parenthesesRe.FindAllIndex(name.b, -1) {
+		info.addQualifier(trimBrackets(name.slice(loc[0], loc[1]).String()))
+	}
+
+	// Delete all parentheses's content.
+	for parenthesesRe.Match(name.b) {
+		name = name.deleteAll(parenthesesRe)
+	}
+
+	// Delete unpaired opening brackets.
+	for _, r := range openBrackets {
+		name = name.deleteRune(r)
+	}
+
+	name = info.cutFeatured(name)
+
+	// Versions often follow the title after a dash, like "Song - Live".
+	if i := strings.LastIndex(string(name.b), " - "); i > 0 {
+		version := name.slice(i+3, len(name.b)).trimSpace()
+		if tags := extractTagsByRegexp(version.b); tags != EmptyTags {
+			info.Tags = info.Tags.Append(tags)
+			info.addQualifier(version.String())
+			name = name.slice(0, i)
+		}
+	}
+
+	info.Work = name.trimSpace().String()
+
+	author, repaired := prepare([]byte(artist))
+	info.Repaired = info.Repaired || repaired
+
+	info.Author = info.cutFeatured(author).trimSpace().String()
+
+	albumName, repaired := prepare([]byte(album))
+	info.Repaired = info.Repaired || repaired
+
+	info.Album = albumName.trimSpace().String()
+	info.Tags = info.Tags.Append(dirTags(albumName.b))
+
+	if info.Remixer != "" {
+		info.Tags = info.Tags.Set(Remix)
+	}
+
+	return info
+}
+
+// addQualifier adds the content of the brackets or the version after a dash.
+// Featured artists are credits, not qualifiers.
+func (info *TitleInfo) addQualifier(q string) {
+	if q == "" {
+		return
+	}
+
+	if loc := featuringRe.FindStringIndex(q); loc != nil && loc[0] == 0 {
+		info.Featured = append(info.Featured, splitArtists(q[loc[1]:])...)
+		return
+	}
+
+	info.Qualifiers = append(info.Qualifiers, q)
+
+	if info.Remixer == "" {
+		info.Remixer = remixer(q)
+	}
+}
+
+// cutFeatured cuts the featured artists off the name.
+func (info *TitleInfo) cutFeatured(name text) text {
+	loc := featuringRe.FindIndex(name.b)
+	if loc == nil {
+		return name
+	}
+
+	featured := name.slice(loc[1], len(name.b)).String()
+	info.Featured = append(info.Featured, splitArtists(featured)...)
+
+	return name.slice(0, loc[0])
+}
+
+// remixer returns the remixer named by the qualifier like "B Remix"
+// or "Remixed by B".
+func remixer(q string) string {
+	if m := remixedByRe.FindStringSubmatch(q); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+
+	m := remixerRe.FindStringSubmatch(q)
+	if m == nil {
+		return ""
+	}
+
+	words := strings.Fields(m[1])
+
+	// Drop the words of the remix name, like "Extended" in "B's Extended Remix".
+	for len(words) > 0 && isVersionWord(words[len(words)-1]) {
+		words = words[:len(words)-1]
+	}
+
+	name := strings.Join(words, " ")
+	name = strings.TrimSuffix(strings.TrimSuffix(name, "'s"), "’s")
+
+	for _, word := range strings.FieldsFunc(name, isNameSeparator) {
+		if !isVersionWord(word) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func isVersionWord(word string) bool {
+	word = strings.ToLower(strings.TrimFunc(word, isNameSeparator))
+	return versionWords[word] || isNumber(word)
+}
+
+// splitArtists splits the list of artists like "B, C & D".
+func splitArtists(s string) (artists []string) {
+	s = strings.TrimSpace(trimBrackets(s))
+
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '&' }) {
+		if part = strings.TrimSpace(part); part != "" {
+			artists = append(artists, part)
+		}
+	}
+
+	return artists
+}
+
+// trimBrackets trims the brackets around the content of parentheses.
+func trimBrackets(s string) string {
+	s = strings.TrimLeft(s, openBrackets)
+	s = strings.TrimRight(s, closeBrackets)
+	return strings.TrimSpace(s)
+}
+
+func isNameSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func isNumber(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) < 0
+}
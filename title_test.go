@@ -0,0 +1,127 @@
+package musicfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractTitleInfo(t *testing.T) {
+	type args struct {
+		title  string
+		artist string
+		album  string
+	}
+	tests := []struct {
+		name string
+		args args
+		want TitleInfo
+	}{
+		{
+			name: "qualifiers",
+			args: args{title: "Song (Live at Wembley) [2011 Remaster]", artist: "Artist", album: "Album"},
+			want: TitleInfo{
+				Info: Info{
+					Author: "Artist",
+					Album:  "Album",
+					Work:   "Song",
+					Tags:   EmptyTags.Set(Live).Set(Remaster),
+				},
+				Qualifiers: []string{"Live at Wembley", "2011 Remaster"},
+			},
+		},
+		{
+			name: "featured artist",
+			args: args{title: "Song", artist: "A feat. B"},
+			want: TitleInfo{
+				Info:     Info{Author: "A", Work: "Song"},
+				Featured: []string{"B"},
+			},
+		},
+		{
+			name: "featured artists in title",
+			args: args{title: "Song (feat. B & C)", artist: "A"},
+			want: TitleInfo{
+				Info:     Info{Author: "A", Work: "Song"},
+				Featured: []string{"B", "C"},
+			},
+		},
+		{
+			name: "featured artist after title",
+			args: args{title: "Song ft. B", artist: "A"},
+			want: TitleInfo{
+				Info:     Info{Author: "A", Work: "Song"},
+				Featured: []string{"B"},
+			},
+		},
+		{
+			name: "remixer",
+			args: args{title: "Song (Someone's Extended Remix)", artist: "A"},
+			want: TitleInfo{
+				Info:       Info{Author: "A", Work: "Song", Tags: EmptyTags.Set(Remix)},
+				Remixer:    "Someone",
+				Qualifiers: []string{"Someone's Extended Remix"},
+			},
+		},
+		{
+			name: "remixed by",
+			args: args{title: "Song [Remixed by Someone]", artist: "A"},
+			want: TitleInfo{
+				Info:       Info{Author: "A", Work: "Song", Tags: EmptyTags.Set(Remix)},
+				Remixer:    "Someone",
+				Qualifiers: []string{"Remixed by Someone"},
+			},
+		},
+		{
+			name: "no remixer",
+			args: args{title: "Song (Original Mix)", artist: "A"},
+			want: TitleInfo{
+				Info:       Info{Author: "A", Work: "Song"},
+				Qualifiers: []string{"Original Mix"},
+			},
+		},
+		{
+			name: "dash is not author",
+			args: args{title: "Part One - Part Two", artist: "A"},
+			want: TitleInfo{
+				Info: Info{Author: "A", Work: "Part One - Part Two"},
+			},
+		},
+		{
+			name: "version after dash",
+			args: args{title: "Song - 2011 Remaster", artist: "A"},
+			want: TitleInfo{
+				Info:       Info{Author: "A", Work: "Song", Tags: EmptyTags.Set(Remaster)},
+				Qualifiers: []string{"2011 Remaster"},
+			},
+		},
+		{
+			name: "dots are kept",
+			args: args{title: "Mr. Big.mp3", artist: "A"},
+			want: TitleInfo{
+				Info: Info{Author: "A", Work: "Mr. Big.mp3"},
+			},
+		},
+		{
+			name: "album tags",
+			args: args{title: "Song", artist: "А", album: "Bootleg Series"},
+			want: TitleInfo{
+				Info: Info{Author: "А", Album: "Bootleg Series", Work: "Song", Tags: EmptyTags.Set(Live)},
+			},
+		},
+		{
+			name: "russian featured",
+			args: args{title: "Песня", artist: "Группа при участии Певца"},
+			want: TitleInfo{
+				Info:     Info{Author: "Группа", Work: "Песня"},
+				Featured: []string{"Певца"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractTitleInfo(tt.args.title, tt.args.artist, tt.args.album); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTitleInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package musicfile
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// translitTable maps Cyrillic letters to their Latin spellings
+// from the GOST, BGN and "volapuk" transliteration schemes.
+var translitTable = map[rune][]string{
+	'а': {"a"},
+	'б': {"b"},
+	'в': {"v", "w"},
+	'г': {"g"},
+	'д': {"d"},
+	'е': {"e", "je", "ye"},
+	'ё': {"e", "jo", "yo", "io"},
+	'ж': {"zh", "j", "z"},
+	'з': {"z"},
+	'и': {"i"},
+	'й': {"j", "y", "i"},
+	'к': {"k"},
+	'л': {"l"},
+	'м': {"m"},
+	'н': {"n"},
+	'о': {"o"},
+	'п': {"p"},
+	'р': {"r"},
+	'с': {"s"},
+	'т': {"t"},
+	'у': {"u"},
+	'ф': {"f"},
+	'х': {"kh", "h", "x"},
+	'ц': {"ts", "tz", "c"},
+	'ч': {"ch", "4"},
+	'ш': {"sh", "w"},
+	'щ': {"shch", "sch", "w"},
+	'ъ': {"", "'", "\""},
+	'ы': {"y", "i"},
+	'ь': {"", "'", "`"},
+	'э': {"e"},
+	'ю': {"yu", "ju", "iu", "u"},
+	'я': {"ya", "ja", "ia", "9"},
+}
+
+// transliterate rewrites the Cyrillic letters of the raw regular expression
+// into groups of their Latin spellings. It reports false when the raw
+// has no Cyrillic letters.
+func transliterate(raw string) (string, bool) {
+	var (
+		b        strings.Builder
+		found    bool
+		escaped  bool
+		inClass  bool
+		spelling []string
+	)
+
+	for _, r := range raw {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '[':
+			inClass = true
+		case r == ']':
+			inClass = false
+		case !inClass && unicode.Is(unicode.Cyrillic, r):
+			spelling = translitTable[unicode.ToLower(r)]
+		}
+
+		if len(spelling) == 0 {
+			b.WriteRune(r)
+			continue
+		}
+
+		found = true
+
+		b.WriteString("(?:")
+		for i, s := range spelling {
+			if i > 0 {
+				b.WriteByte('|')
+			}
+			b.WriteString(regexp.QuoteMeta(s))
+		}
+		b.WriteByte(')')
+
+		spelling = nil
+	}
+
+	return b.String(), found
+}
+
+// withTranslit appends the transliterated variants of the raws.
+// A variant is dropped when it matches any of the excluded keywords,
+// e.g. "радио" is a remix word, but "radio" is a radio edit. The stems
+// shorter than minTranslitLetters aren't transliterated, "bas" of "бас"
+// is a part of "Base" and "Basic". A variant starts at the beginning of
+// a word, so "9" of "я" doesn't match inside other words.
+func withTranslit(raws []string, exclude []string) []string {
+	dst := append([]string(nil), raws...)
+
+	for _, raw := range raws {
+		if cyrillicLetters(raw) < minTranslitLetters {
+			continue
+		}
+
+		variant, ok := transliterate(raw)
+		if !ok {
+			continue
+		}
+
+		if r, _ := utf8.DecodeRuneInString(raw); unicode.IsLetter(r) {
+			variant = `\b` + variant
+		}
+
+		re := regexp.MustCompile("^(?:" + variant + ")$")
+
+		if !matchesAny(re, exclude) {
+			dst = append(dst, variant)
+		}
+	}
+
+	return dst
+}
+
+// minTranslitLetters is the length of the shortest stem that is transliterated.
+const minTranslitLetters = 4
+
+func cyrillicLetters(raw string) (n int) {
+	for _, r := range raw {
+		if unicode.Is(unicode.Cyrillic, r) {
+			n++
+		}
+	}
+	return n
+}
+
+func matchesAny(re *regexp.Regexp, keywords []string) bool {
+	for _, kw := range keywords {
+		if re.MatchString(kw) {
+			return true
+		}
+	}
+	return false
+}
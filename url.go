@@ -0,0 +1,116 @@
+package musicfile
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// ExtractURLInfo extracts music info from the URL of a track,
+// e.g. "file:///home/u/Music/Artist%20-%20Song.mp3", an HTTP download link
+// or a DLNA resource. The scheme, host, query and fragment are dropped,
+// the path is percent-decoded. HTML entities like "&amp;" are decoded too,
+// but not the ones that are percent-encoded like "%26amp%3B".
+func ExtractURLInfo(rawURL string) (Info, error) {
+	path, err := SplitURL(rawURL)
+	if err != nil {
+		return Info{}, err
+	}
+	return ExtractPathInfo(path), nil
+}
+
+// SplitURL splits the path of the URL into percent-decoded segments.
+// An encoded slash "%2F" stays inside its segment. HTML entities are
+// decoded before the percent-encoding, so "&#35;" and "&#63;" don't cut
+// the path. A percent sign that doesn't start an escape, like in
+// "100% Hits", is kept. The raw path is split when the URL can't be parsed.
+func SplitURL(rawURL string) ([][]byte, error) {
+	rawURL = strings.TrimSpace(rawURL)
+
+	// The decoded entities are escaped again, so they are decoded once.
+	rawURL = entityRe.ReplaceAllStringFunc(rawURL, func(entity string) string {
+		return url.PathEscape(html.UnescapeString(entity))
+	})
+	rawURL = escapeBarePercents(rawURL)
+
+	var segments []string
+
+	if u, err := url.Parse(rawURL); err == nil {
+		segments = strings.Split(u.EscapedPath(), "/")
+
+		if strings.EqualFold(u.Scheme, "file") {
+			segments = trimFileVolume(u.Host, segments)
+		}
+	} else {
+		segments = strings.Split(rawURLPath(rawURL), "/")
+	}
+
+	path := make([][]byte, 0, len(segments))
+
+	for _, seg := range segments {
+		s, err := url.PathUnescape(seg)
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, []byte(s))
+	}
+
+	return path, nil
+}
+
+// escapeBarePercents escapes the percent signs that aren't followed
+// by two hexadecimal digits.
+func escapeBarePercents(s string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		sb.WriteByte(s[i])
+		if s[i] == '%' && (i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2])) {
+			sb.WriteString("25")
+		}
+	}
+
+	return sb.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c|0x20 && c|0x20 <= 'f'
+}
+
+// rawURLPath cuts the scheme, the host, the query and the fragment
+// off the URL that url.Parse rejects.
+func rawURLPath(rawURL string) string {
+	if i := strings.Index(rawURL, "://"); i >= 0 {
+		rawURL = rawURL[i+3:]
+
+		j := strings.IndexByte(rawURL, '/')
+		if j < 0 {
+			return ""
+		}
+		rawURL = rawURL[j:]
+	}
+
+	if i := strings.IndexAny(rawURL, "?#"); i >= 0 {
+		rawURL = rawURL[:i]
+	}
+
+	return rawURL
+}
+
+// trimFileVolume trims the drive letter of "file:///C:/…" and the share
+// of "file://host/share/…" URLs.
+func trimFileVolume(host string, segments []string) []string {
+	if len(segments) < 2 || segments[0] != "" {
+		return segments
+	}
+
+	if isDrive([]byte(segments[1])) {
+		return append(segments[:1], segments[2:]...)
+	}
+
+	if host != "" && !strings.EqualFold(host, "localhost") {
+		return append(segments[:1], segments[2:]...)
+	}
+
+	return segments
+}
@@ -0,0 +1,153 @@
+package musicfile
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractURLInfo(t *testing.T) {
+	type args struct {
+		rawURL string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		wantInfo Info
+		wantErr  bool
+	}{
+		{
+			name: "file",
+			args: args{rawURL: "file:///home/u/Music/Artist%20-%20Song%20(Live).mp3"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				Tags:          EmptyTags.Set(Live),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "file drive",
+			args: args{rawURL: "file:///C:/Music/Artist%20-%20Song.mp3"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "file share",
+			args: args{rawURL: "file://nas/live/Artist%20-%20Song.mp3"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "http query",
+			args: args{rawURL: "https://example.com/dl/Artist%20-%20Song%20%5BRemix%5D.mp3?token=live&amp;x=1#bonus"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				Tags:          EmptyTags.Set(Remix),
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "html entities",
+			args: args{rawURL: "http://192.168.1.2:8200/MediaItems/Simon%20&amp;%20Garfunkel%20-%20America.flac"},
+			wantInfo: Info{
+				Author:        "Simon & Garfunkel",
+				Work:          "America",
+				FileExtension: ".flac",
+				Family:        FamilyFLAC,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "numeric entities",
+			args: args{rawURL: "http://host/Artist%20-%20Song%20&#35;1%20&#63;.mp3#frag"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song #1 ?",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "encoded slash",
+			args: args{rawURL: "http://host/AC%2FDC%20-%20Thunderstruck.mp3"},
+			wantInfo: Info{
+				Author:        "AC/DC",
+				Work:          "Thunderstruck",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "encoded entity",
+			args: args{rawURL: "http://host/Artist%20-%20Rock%20%26amp%3B%20Roll.mp3"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Rock &amp; Roll",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "bare percent",
+			args: args{rawURL: "http://host/100% Hits/Artist%20-%20Song.mp3"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "bad escape",
+			args: args{rawURL: "http://host/a%zz.mp3"},
+			wantInfo: Info{
+				Work:          "a%zz",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+		{
+			name: "unparsed url",
+			args: args{rawURL: "http://host:port/Artist%20-%20Song.mp3?x=1"},
+			wantInfo: Info{
+				Author:        "Artist",
+				Work:          "Song",
+				FileExtension: ".mp3",
+				Family:        FamilyMPEG,
+				Kind:          KindAudio,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInfo, err := ExtractURLInfo(tt.args.rawURL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExtractURLInfo() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotInfo, tt.wantInfo) {
+				t.Errorf("ExtractURLInfo() = %v, want %v", gotInfo, tt.wantInfo)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package musicfile
+
+import (
+	"encoding/binary"
+	"strings"
+)
+
+// vorbisFields maps the Vorbis comment field names to the fields of the metadata.
+var vorbisFields = map[string]string{
+	"ARTIST":      "artist",
+	"ALBUMARTIST": "albumartist",
+	"TITLE":       "title",
+	"VERSION":     "version",
+	"SUBTITLE":    "version",
+	"ALBUM":       "album",
+	"TRACKNUMBER": "track",
+	"DISCNUMBER":  "disc",
+	"DATE":        "year",
+	"YEAR":        "year",
+}
+
+// parseVorbisComment parses the Vorbis comment used by FLAC, Vorbis and Opus.
+// Only the first value of every field is kept.
+func parseVorbisComment(data []byte) (metadata, error) {
+	// Skip the vendor string.
+	_, data, ok := vorbisString(data)
+	if !ok || len(data) < 4 {
+		return metadata{}, ErrMalformedMetadata
+	}
+
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	fields := metadataFields{}
+
+	for i := uint32(0); i < count; i++ {
+		var comment string
+
+		comment, data, ok = vorbisString(data)
+		if !ok {
+			return metadata{}, ErrMalformedMetadata
+		}
+
+		name, value, found := strings.Cut(comment, "=")
+		if !found {
+			continue
+		}
+
+		field, known := vorbisFields[strings.ToUpper(name)]
+		if !known {
+			continue
+		}
+
+		if _, dup := fields[field]; !dup {
+			fields[field] = strings.TrimSpace(value)
+		}
+	}
+
+	return fields.metadata(), nil
+}
+
+// vorbisString reads a string prefixed with its 32-bit little endian length.
+func vorbisString(data []byte) (s string, rest []byte, ok bool) {
+	if len(data) < 4 {
+		return "", nil, false
+	}
+
+	n := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	if uint64(n) > uint64(len(data)) {
+		return "", nil, false
+	}
+
+	return string(data[:n]), data[n:], true
+}
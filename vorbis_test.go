@@ -0,0 +1,257 @@
+package musicfile
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestReadFLAC(t *testing.T) {
+	comment := testVorbisComment(
+		"ARTIST=Artist",
+		"ARTIST=Second Artist",
+		"title=Song (Live)",
+		"ALBUM=Album",
+		"TRACKNUMBER=04",
+		"DISCNUMBER=2/2",
+		"DATE=2011-05-01",
+		"VERSION=Acoustic Instrumental",
+	)
+
+	tests := []struct {
+		name     string
+		file     []byte
+		wantInfo Info
+		wantErr  error
+	}{
+		{
+			name: "vorbis comment",
+			file: concat(
+				[]byte("fLaC"),
+				testFLACBlock(flacBlockStreamInfo, false, make([]byte, 34)),
+				testFLACBlock(flacBlockVorbisComment, true, comment),
+			),
+			wantInfo: Info{
+				Author: "Artist",
+				Album:  "Album",
+				Work:   "Song",
+				Track:  4,
+				Disc:   2,
+				Year:   2011,
+				Tags:   EmptyTags.Set(Live).Set(Instrumental),
+			},
+		},
+		{
+			name: "id3 before stream",
+			file: concat(
+				testID3v2Tag(3, 0, testID3Frame(3, "TIT2", latin1Text("Ignored"))),
+				[]byte("fLaC"),
+				testFLACBlock(flacBlockVorbisComment, true, testVorbisComment("TITLE=Song")),
+			),
+			wantInfo: Info{
+				Work: "Song",
+			},
+		},
+		{
+			name: "no comment",
+			file: concat(
+				[]byte("fLaC"),
+				testFLACBlock(flacBlockStreamInfo, true, make([]byte, 34)),
+			),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "not flac",
+			file:    []byte("RIFF"),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name: "broken comment",
+			file: concat(
+				[]byte("fLaC"),
+				testFLACBlock(flacBlockVorbisComment, true, comment[:20]),
+			),
+			wantErr: ErrMalformedMetadata,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInfo, err := ReadFLAC(bytes.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadFLAC() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotInfo, tt.wantInfo) {
+				t.Errorf("ReadFLAC() = %+v, want %+v", gotInfo, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestReadOgg(t *testing.T) {
+	comment := testVorbisComment("ARTIST=Artist", "TITLE=Song", "VERSION=Remix")
+
+	tests := []struct {
+		name     string
+		file     []byte
+		wantInfo Info
+		wantErr  error
+	}{
+		{
+			name: "vorbis",
+			file: concat(
+				testOggPage(1, []byte("\x01vorbis....")),
+				testOggPage(1, concat([]byte("\x03vorbis"), comment, []byte{1})),
+			),
+			wantInfo: Info{
+				Author: "Artist",
+				Work:   "Song",
+				Tags:   EmptyTags.Set(Remix),
+			},
+		},
+		{
+			name: "opus spanning pages",
+			file: func() []byte {
+				packet := concat([]byte("OpusTags"), testVorbisComment(
+					"TITLE=Song",
+					"METADATA_BLOCK_PICTURE="+string(bytes.Repeat([]byte{'A'}, 600)),
+				))
+				return concat(
+					testOggPage(7, []byte("OpusHead....")),
+					testOggPage(9, []byte("other stream")),
+					testOggPagePart(7, packet[:510], true),
+					testOggPage(7, packet[510:]),
+				)
+			}(),
+			wantInfo: Info{
+				Work: "Song",
+			},
+		},
+		{
+			name: "flac",
+			file: concat(
+				testOggPage(1, []byte("\x7fFLAC....")),
+				testOggPage(1, testFLACBlock(flacBlockVorbisComment, false, comment)),
+			),
+			wantInfo: Info{
+				Author: "Artist",
+				Work:   "Song",
+				Tags:   EmptyTags.Set(Remix),
+			},
+		},
+		{
+			name:    "unknown codec",
+			file:    concat(testOggPage(1, []byte("\x80theora")), testOggPage(1, []byte("x"))),
+			wantErr: ErrNoMetadata,
+		},
+		{
+			name:    "truncated",
+			file:    testOggPage(1, []byte("OpusHead....")),
+			wantErr: ErrMalformedMetadata,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotInfo, err := ReadOgg(bytes.NewReader(tt.file))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadOgg() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(gotInfo, tt.wantInfo) {
+				t.Errorf("ReadOgg() = %+v, want %+v", gotInfo, tt.wantInfo)
+			}
+		})
+	}
+}
+
+func TestExtractFileInfo(t *testing.T) {
+	file := concat(
+		[]byte("fLaC"),
+		testFLACBlock(flacBlockVorbisComment, true, testVorbisComment("TITLE=Song", "TRACKNUMBER=1")),
+	)
+
+	gotInfo, err := ExtractFileInfo([]byte("Artist/Artist - track (Demo).flac"), bytes.NewReader(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantInfo := Info{
+		Author:        "Artist",
+		Work:          "Song",
+		Track:         1,
+		Tags:          EmptyTags.Set(Demo),
+		FileExtension: ".flac",
+		Family:        FamilyFLAC,
+		Kind:          KindAudio,
+	}
+	if !reflect.DeepEqual(gotInfo, wantInfo) {
+		t.Errorf("ExtractFileInfo() = %+v, want %+v", gotInfo, wantInfo)
+	}
+}
+
+func TestExtractFileInfo_malformed(t *testing.T) {
+	file := []byte("ID3\x04\x00\x00\x7f\x7f\x7f\x7f")
+
+	gotInfo, err := ExtractFileInfo([]byte("Artist/Artist - track (Demo).flac"), bytes.NewReader(file))
+	if !errors.Is(err, ErrMalformedMetadata) {
+		t.Fatalf("ExtractFileInfo() error = %v, wantErr %v", err, ErrMalformedMetadata)
+	}
+
+	wantInfo := Info{
+		Author:        "Artist",
+		Work:          "track",
+		Tags:          EmptyTags.Set(Demo),
+		FileExtension: ".flac",
+		Family:        FamilyFLAC,
+		Kind:          KindAudio,
+	}
+	if !reflect.DeepEqual(gotInfo, wantInfo) {
+		t.Errorf("ExtractFileInfo() = %+v, want %+v", gotInfo, wantInfo)
+	}
+}
+
+func testVorbisComment(comments ...string) []byte {
+	b := binary.LittleEndian.AppendUint32(nil, 6)
+	b = append(b, "vendor"...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(comments)))
+	for _, c := range comments {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(c)))
+		b = append(b, c...)
+	}
+	return b
+}
+
+func testFLACBlock(kind byte, last bool, data []byte) []byte {
+	if last {
+		kind |= 0x80
+	}
+	n := len(data)
+	return append([]byte{kind, byte(n >> 16), byte(n >> 8), byte(n)}, data...)
+}
+
+func testOggPage(serial uint32, packet []byte) []byte {
+	return testOggPagePart(serial, packet, false)
+}
+
+// testOggPagePart makes a page with the packet, which is continued
+// on the next page when more is true. The length of a continued
+// packet must be a multiple of 255.
+func testOggPagePart(serial uint32, packet []byte, more bool) []byte {
+	var segments []byte
+
+	n := len(packet)
+	for n >= 255 {
+		segments = append(segments, 255)
+		n -= 255
+	}
+	if !more {
+		segments = append(segments, byte(n))
+	}
+
+	header := make([]byte, oggPageHeaderSize)
+	copy(header, "OggS")
+	binary.LittleEndian.PutUint32(header[14:], serial)
+	header[26] = byte(len(segments))
+
+	return concat(header, segments, packet)
+}